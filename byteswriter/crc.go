@@ -0,0 +1,140 @@
+package byteswriter
+
+import (
+	"hash/crc32"
+	"sort"
+
+	"github.com/vimeo/go-util/crc32combine"
+)
+
+// DefaultCRCBlockSize is the block size used by EnableCRC32 when none is
+// given.
+const DefaultCRCBlockSize = 64 * 1024
+
+// crcNode is the CRC-32 of a span of the buffer and the length it covers.
+type crcNode struct {
+	crc uint32
+	n   int64
+}
+
+// crcTracker keeps the CRC-32 of each block of the buffer in the leaves of a
+// segment tree, whose inner nodes hold the combined CRC-32 of their children.
+type crcTracker struct {
+	comb      *crc32combine.Combiner
+	tab       *crc32.Table
+	blockSize int
+	leaves    int
+	nodes     []crcNode
+	dirty     map[int]struct{}
+}
+
+// EnableCRC32 makes the Writer keep track of the CRC-32 of its contents,
+// using the generator polynomial poly, as passed to crc32.MakeTable. The
+// buffer is divided in blocks of blockSize bytes, and only blocks that have
+// been written to are rehashed when the checksum is next requested. If
+// blockSize is not positive, DefaultCRCBlockSize is used.
+func (w *Writer) EnableCRC32(poly uint32, blockSize int) {
+	if blockSize <= 0 {
+		blockSize = DefaultCRCBlockSize
+	}
+	w.crc = &crcTracker{
+		comb:      crc32combine.NewCombiner(poly),
+		tab:       crc32.MakeTable(poly),
+		blockSize: blockSize,
+		dirty:     make(map[int]struct{}),
+	}
+	w.crc.invalidate(0, len(w.buf))
+}
+
+// CRC32 returns the CRC-32 of the contents of the buffer. It returns 0 if
+// EnableCRC32 has not been called. Changes made through the slice returned by
+// Bytes are not tracked.
+func (w *Writer) CRC32() uint32 {
+	if w.crc == nil {
+		return 0
+	}
+	return w.crc.sum(w.buf)
+}
+
+// invalidate marks the blocks covering buf[start:end] as needing a rehash.
+func (t *crcTracker) invalidate(start, end int) {
+	if start >= end {
+		return
+	}
+	for b := start / t.blockSize; b <= (end-1)/t.blockSize; b++ {
+		t.dirty[b] = struct{}{}
+	}
+}
+
+func (t *crcTracker) combine(l, r crcNode) crcNode {
+	if l.n == 0 {
+		return r
+	}
+	return crcNode{
+		crc: t.comb.Combine(l.crc, r.crc, r.n),
+		n:   l.n + r.n,
+	}
+}
+
+// grow resizes the tree so that it has at least blocks leaves.
+func (t *crcTracker) grow(blocks int) {
+	leaves := t.leaves
+	if leaves == 0 {
+		leaves = 1
+	}
+	for leaves < blocks {
+		leaves *= 2
+	}
+	if leaves == t.leaves {
+		return
+	}
+
+	nodes := make([]crcNode, 2*leaves)
+	if t.leaves > 0 {
+		copy(nodes[leaves:], t.nodes[t.leaves:])
+	}
+	for i := leaves - 1; i >= 1; i-- {
+		nodes[i] = t.combine(nodes[2*i], nodes[2*i+1])
+	}
+	t.leaves = leaves
+	t.nodes = nodes
+}
+
+// sum rehashes the dirty blocks of buf, updates their ancestors in the tree
+// and returns the CRC-32 at the root.
+func (t *crcTracker) sum(buf []byte) uint32 {
+	t.grow((len(buf) + t.blockSize - 1) / t.blockSize)
+	if len(t.dirty) == 0 {
+		return t.nodes[1].crc
+	}
+
+	level := make([]int, 0, len(t.dirty))
+	for b := range t.dirty {
+		start := b * t.blockSize
+		end := start + t.blockSize
+		if end > len(buf) {
+			end = len(buf)
+		}
+		t.nodes[t.leaves+b] = crcNode{
+			crc: crc32.Checksum(buf[start:end], t.tab),
+			n:   int64(end - start),
+		}
+		level = append(level, t.leaves+b)
+		delete(t.dirty, b)
+	}
+	sort.Ints(level)
+
+	for level[0] > 1 {
+		parents := level[:0]
+		for _, i := range level {
+			p := i / 2
+			if len(parents) > 0 && parents[len(parents)-1] == p {
+				continue
+			}
+			t.nodes[p] = t.combine(t.nodes[2*p], t.nodes[2*p+1])
+			parents = append(parents, p)
+		}
+		level = parents
+	}
+	return t.nodes[1].crc
+}
@@ -0,0 +1,52 @@
+package byteswriter
+
+import (
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestCRC32Tracking(t *testing.T) {
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		rng := rand.New(rand.NewSource(int64(poly)))
+
+		w := New()
+		w.Write(make([]byte, 100))
+		w.EnableCRC32(poly, 64)
+		if got, want := w.CRC32(), crc32.Checksum(w.Bytes(), tab); got != want {
+			t.Errorf("poly %#x: initial CRC %#x, want %#x", poly, got, want)
+		}
+
+		for i := 0; i < 200; i++ {
+			buf := make([]byte, rng.Intn(300))
+			rng.Read(buf)
+			if rng.Intn(2) == 0 {
+				w.Seek(0, io.SeekEnd)
+			} else {
+				w.Seek(rng.Int63n(w.Size()+1), io.SeekStart)
+			}
+			if _, err := w.Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := w.CRC32(), crc32.Checksum(w.Bytes(), tab); got != want {
+				t.Fatalf("poly %#x, iteration %d: CRC %#x, want %#x", poly, i, got, want)
+			}
+		}
+	}
+}
+
+func TestCRC32Disabled(t *testing.T) {
+	w := New()
+	w.Write([]byte("abc"))
+	if crc := w.CRC32(); crc != 0 {
+		t.Errorf("got %#x without tracking enabled", crc)
+	}
+
+	w = New()
+	w.EnableCRC32(crc32.IEEE, 0)
+	if crc := w.CRC32(); crc != 0 {
+		t.Errorf("got %#x for empty buffer", crc)
+	}
+}
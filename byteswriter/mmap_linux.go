@@ -0,0 +1,149 @@
+//go:build linux
+// +build linux
+
+package byteswriter
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// minMmapSize is the smallest mapping an MmapWriter will create.
+const minMmapSize = 64 * 1024
+
+// An MmapWriter implements the same WriteSeeker interface as Writer, but
+// is backed by a memory-mapped file instead of memory on the Go heap. The
+// file and its mapping are grown as necessary, so while the MmapWriter is
+// open the file may be larger than the written content.
+type MmapWriter struct {
+	f    *os.File
+	data []byte
+	size int
+	pos  int
+}
+
+// NewMmap returns a new MmapWriter backed by f, which must be opened for
+// reading and writing. The existing contents of f are the initial contents
+// of the buffer, and the seek position starts at 0. The MmapWriter does not
+// take ownership of f; it must still be closed by the caller after Close.
+func NewMmap(f *os.File) (*MmapWriter, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &MmapWriter{f: f, size: int(fi.Size())}
+	if w.size > 0 {
+		if err := w.remap(w.size); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// remap replaces the current mapping with one of n bytes, growing the file
+// to match.
+func (w *MmapWriter) remap(n int) error {
+	if err := w.unmap(); err != nil {
+		return err
+	}
+	if err := w.f.Truncate(int64(n)); err != nil {
+		return err
+	}
+	data, err := syscall.Mmap(int(w.f.Fd()), 0, n, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	w.data = data
+	return nil
+}
+
+func (w *MmapWriter) unmap() error {
+	if w.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(w.data)
+	w.data = nil
+	return err
+}
+
+// Size returns the current size of the buffer.
+func (w *MmapWriter) Size() int64 {
+	return int64(w.size)
+}
+
+// Seek seeks to a given offset in a buffer.
+func (w *MmapWriter) Seek(offset int64, whence int) (int64, error) {
+	off, err := seekOffset(int64(w.pos), int64(w.size), offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	w.pos = int(off)
+
+	return off, nil
+}
+
+// Write writes to the mapped file and increases size as necessary.
+func (w *MmapWriter) Write(buf []byte) (int, error) {
+	if w.f == nil {
+		return 0, fmt.Errorf("write to closed MmapWriter")
+	}
+	if w.pos > w.size {
+		return 0, fmt.Errorf("Cannot write while past end of buffer.")
+	}
+
+	end := w.pos + len(buf)
+	if end > len(w.data) {
+		n := len(w.data)
+		if n < minMmapSize {
+			n = minMmapSize
+		}
+		for n < end {
+			n *= 2
+		}
+		if err := w.remap(n); err != nil {
+			return 0, err
+		}
+	}
+
+	copy(w.data[w.pos:], buf)
+	w.pos = end
+	if end > w.size {
+		w.size = end
+	}
+	return len(buf), nil
+}
+
+// Bytes returns the mapped content. The slice is valid for use only until the
+// next write or Close. The slice aliases the mapped file, so changes to the
+// slice will affect the content of the writer itself.
+func (w *MmapWriter) Bytes() []byte {
+	if w.data == nil {
+		return nil
+	}
+	return w.data[:w.size]
+}
+
+// Close flushes the mapping to the file, unmaps it, and truncates the file to
+// the size of the written content.
+func (w *MmapWriter) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	if len(w.data) > 0 {
+		_, _, errno := syscall.Syscall(syscall.SYS_MSYNC,
+			uintptr(unsafe.Pointer(&w.data[0])), uintptr(len(w.data)), syscall.MS_SYNC)
+		if errno != 0 {
+			return errno
+		}
+	}
+	if err := w.unmap(); err != nil {
+		return err
+	}
+	err := w.f.Truncate(int64(w.size))
+	w.f = nil
+	return err
+}
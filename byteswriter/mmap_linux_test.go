@@ -0,0 +1,89 @@
+//go:build linux
+// +build linux
+
+package byteswriter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMmapWriter(t *testing.T) {
+	f, err := ioutil.TempFile("", "byteswriter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	w, err := NewMmap(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8}); err != nil {
+		t.Fatal(err)
+	}
+	if i, err := w.Seek(-6, io.SeekCurrent); err != nil || i != 2 {
+		t.Errorf("Seek = %d, %v", i, err)
+	}
+	if _, err := w.Write([]byte{11, 12, 13, 14}); err != nil {
+		t.Fatal(err)
+	}
+	if i, err := w.Seek(2, io.SeekEnd); err != nil || i != 6 {
+		t.Errorf("Seek = %d, %v", i, err)
+	}
+	if _, err := w.Write([]byte{21, 22, 23, 24}); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{1, 2, 11, 12, 13, 14, 21, 22, 23, 24}
+	if !bytes.Equal(w.Bytes(), want) {
+		t.Errorf("got %v, want %v", w.Bytes(), want)
+	}
+
+	// Grow past the initial mapping.
+	big := bytes.Repeat([]byte{0xaa}, 3*minMmapSize)
+	if _, err := w.Write(big); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, big...)
+	if w.Size() != int64(len(want)) {
+		t.Errorf("got size %d, want %d", w.Size(), len(want))
+	}
+
+	w.Seek(1, io.SeekEnd)
+	if _, err := w.Write(make([]byte, 10)); err != nil {
+		t.Error(err)
+	}
+	want = append(want[:len(want)-1], make([]byte, 10)...)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("file content mismatch: got %d bytes, want %d", len(got), len(want))
+	}
+
+	// Reopen the existing content.
+	w, err = NewMmap(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(w.Bytes(), want) {
+		t.Errorf("reopened content mismatch")
+	}
+	w.Seek(int64(len(want))+1, io.SeekStart)
+	if _, err := w.Write([]byte{1}); err == nil {
+		t.Errorf("writing while seeked past end of buffer should not work.")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+package byteswriter
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// snapshotPageSize is the granularity at which a Snapshot preserves content
+// that is overwritten in its Writer.
+const snapshotPageSize = 4 * 1024
+
+// A Snapshot is an immutable view of the contents of a Writer at the time
+// Snapshot was called. It shares the buffer of its Writer, and a page of the
+// buffer is only copied into the Snapshot when the Writer is about to
+// overwrite it. A Snapshot may be read from a different goroutine than the
+// one writing to its Writer.
+type Snapshot struct {
+	mu       sync.Mutex
+	size     int64
+	buf      []byte
+	pages    map[int][]byte
+	released bool
+}
+
+// Snapshot returns a read-only view of the current contents of the buffer.
+// The returned Snapshot stays valid and unchanged across subsequent writes.
+// Changes made through the slice returned by Bytes are not tracked, and will
+// be visible in the Snapshot.
+func (w *Writer) Snapshot() *Snapshot {
+	s := &Snapshot{
+		size:  int64(len(w.buf)),
+		buf:   w.buf,
+		pages: make(map[int][]byte),
+	}
+	w.snaps = append(w.snaps, s)
+	return s
+}
+
+// preserve copies the pages covering buf[start:end] into every live
+// snapshot that still refers to them, prior to them being overwritten.
+func (w *Writer) preserve(start, end int) {
+	live := w.snaps[:0]
+	for _, s := range w.snaps {
+		if s.preserve(start, end) {
+			live = append(live, s)
+		}
+	}
+	for i := len(live); i < len(w.snaps); i++ {
+		w.snaps[i] = nil
+	}
+	w.snaps = live
+}
+
+// preserve copies the pages covering buf[start:end] that have not been
+// copied yet. It returns false if the snapshot has been released.
+func (s *Snapshot) preserve(start, end int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.released {
+		return false
+	}
+	if start >= len(s.buf) {
+		return true
+	}
+	if end > len(s.buf) {
+		end = len(s.buf)
+	}
+	for off := start - start%snapshotPageSize; off < end; off += snapshotPageSize {
+		n := off / snapshotPageSize
+		if _, ok := s.pages[n]; ok {
+			continue
+		}
+		pend := off + snapshotPageSize
+		if pend > len(s.buf) {
+			pend = len(s.buf)
+		}
+		s.pages[n] = append([]byte(nil), s.buf[off:pend]...)
+	}
+	return true
+}
+
+// Size returns the size of the snapshot.
+func (s *Snapshot) Size() int64 {
+	return s.size
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *Snapshot) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.released {
+		return 0, fmt.Errorf("read from released snapshot")
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	if off >= int64(len(s.buf)) {
+		return 0, io.EOF
+	}
+
+	pos := int(off)
+	n := 0
+	for n < len(p) && pos < len(s.buf) {
+		page := pos / snapshotPageSize
+		start := page * snapshotPageSize
+		src, ok := s.pages[page]
+		if !ok {
+			end := start + snapshotPageSize
+			if end > len(s.buf) {
+				end = len(s.buf)
+			}
+			src = s.buf[start:end]
+		}
+		c := copy(p[n:], src[pos-start:])
+		n += c
+		pos += c
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Release detaches the snapshot from its Writer, so that further writes no
+// longer copy pages on its behalf. The snapshot must not be read afterwards.
+func (s *Snapshot) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.released = true
+	s.buf = nil
+	s.pages = nil
+}
@@ -0,0 +1,134 @@
+package byteswriter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func readSnapshot(t *testing.T, s *Snapshot) []byte {
+	b, err := ioutil.ReadAll(io.NewSectionReader(s, 0, s.Size()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSnapshot(t *testing.T) {
+	data := make([]byte, 3*snapshotPageSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	w := NewPreallocated(8 * snapshotPageSize)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	s := w.Snapshot()
+
+	// Patch the middle of the second page, and append within capacity.
+	if _, err := w.Seek(snapshotPageSize+10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte{0xff, 0xff, 0xff}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.pages) != 1 {
+		t.Errorf("got %d copied pages, want 1", len(s.pages))
+	}
+	if got := readSnapshot(t, s); !bytes.Equal(got, data) {
+		t.Errorf("snapshot changed after write")
+	}
+	if w.Bytes()[snapshotPageSize+10] != 0xff {
+		t.Errorf("write did not reach the writer")
+	}
+
+	// Overwrite across the tail of the buffer, forcing a reallocation.
+	if _, err := w.Seek(5, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(make([]byte, 8*snapshotPageSize)); err != nil {
+		t.Fatal(err)
+	}
+	if got := readSnapshot(t, s); !bytes.Equal(got, data) {
+		t.Errorf("snapshot changed after reallocation")
+	}
+	if len(w.snaps) != 0 {
+		t.Errorf("snapshots still attached after reallocation")
+	}
+}
+
+func TestSnapshotReadAt(t *testing.T) {
+	w := New()
+	w.Write([]byte("hello, world"))
+	s := w.Snapshot()
+	w.Seek(0, io.SeekStart)
+	w.Write([]byte("HELLO"))
+
+	b := make([]byte, 5)
+	n, err := s.ReadAt(b, 0)
+	if err != nil || n != 5 || string(b) != "hello" {
+		t.Errorf("ReadAt(0) = %d, %v, %q", n, err, b)
+	}
+	n, err = s.ReadAt(b, 10)
+	if err != io.EOF || n != 2 || string(b[:n]) != "ld" {
+		t.Errorf("ReadAt(10) = %d, %v, %q", n, err, b[:n])
+	}
+	if _, err = s.ReadAt(b, 12); err != io.EOF {
+		t.Errorf("ReadAt past end returned %v", err)
+	}
+}
+
+func TestSnapshotWritePastEnd(t *testing.T) {
+	w := NewPreallocated(4 * snapshotPageSize)
+	w.Write(make([]byte, 100))
+	s := w.Snapshot()
+
+	// Overwriting the tail of the buffer past the end of the snapshot
+	// must not copy any pages.
+	w.Write(make([]byte, 100))
+	w.WriteAt([]byte{1}, 150)
+	if len(s.pages) != 0 {
+		t.Errorf("got %d copied pages, want 0", len(s.pages))
+	}
+}
+
+func TestSnapshotSizeAfterRelease(t *testing.T) {
+	w := New()
+	w.Write([]byte("abcdef"))
+	s := w.Snapshot()
+
+	done := make(chan struct{})
+	go func() {
+		s.Release()
+		close(done)
+	}()
+	if size := s.Size(); size != 6 {
+		t.Errorf("got size %d, want 6", size)
+	}
+	<-done
+}
+
+func TestSnapshotRelease(t *testing.T) {
+	w := New()
+	w.Write([]byte("abcdef"))
+	s := w.Snapshot()
+	s.Release()
+	w.Seek(0, io.SeekStart)
+	w.Write([]byte("x"))
+
+	if len(w.snaps) != 0 {
+		t.Errorf("released snapshot still attached")
+	}
+	if _, err := s.ReadAt(make([]byte, 1), 0); err == nil {
+		t.Errorf("read from released snapshot succeeded")
+	}
+}
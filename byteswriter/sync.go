@@ -0,0 +1,69 @@
+package byteswriter
+
+import (
+	"sync"
+)
+
+// A SyncWriter wraps a Writer so that it is safe for concurrent use.
+// Write, Seek and WriteAt are serialized with respect to each other, while
+// ReadAt and Size may run concurrently with one another.
+type SyncWriter struct {
+	mu sync.RWMutex
+	w  *Writer
+}
+
+// NewSync returns a SyncWriter wrapping w. w must not be used directly
+// while the SyncWriter is in use.
+func NewSync(w *Writer) *SyncWriter {
+	return &SyncWriter{w: w}
+}
+
+// Size returns the current size of the buffer.
+func (s *SyncWriter) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.w.Size()
+}
+
+// Seek seeks to a given offset in the buffer.
+func (s *SyncWriter) Seek(offset int64, whence int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Seek(offset, whence)
+}
+
+// Write writes to the buffer at the current seek position.
+func (s *SyncWriter) Write(buf []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(buf)
+}
+
+// WriteAt writes to the buffer at offset off.
+func (s *SyncWriter) WriteAt(buf []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.WriteAt(buf, off)
+}
+
+// ReadAt reads from the buffer at offset off.
+func (s *SyncWriter) ReadAt(buf []byte, off int64) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.w.ReadAt(buf, off)
+}
+
+// Snapshot returns a Snapshot of the current contents of the buffer.
+func (s *SyncWriter) Snapshot() *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Snapshot()
+}
+
+// CRC32 returns the CRC-32 of the contents of the buffer, as tracked by
+// Writer.EnableCRC32.
+func (s *SyncWriter) CRC32() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.CRC32()
+}
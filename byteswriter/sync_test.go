@@ -0,0 +1,94 @@
+package byteswriter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestWriteAtReadAt(t *testing.T) {
+	w := New()
+	w.Write([]byte("abcdef"))
+	if n, err := w.WriteAt([]byte("XY"), 2); err != nil || n != 2 {
+		t.Errorf("WriteAt = %d, %v", n, err)
+	}
+	if _, err := w.WriteAt([]byte("Z"), 7); err == nil {
+		t.Errorf("WriteAt past end of buffer should not work")
+	}
+	if _, err := w.Write([]byte("g")); err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(w.Bytes(), []byte("abXYefg")) {
+		t.Errorf("got %q", w.Bytes())
+	}
+
+	b := make([]byte, 4)
+	if n, err := w.ReadAt(b, 1); err != nil || n != 4 || string(b) != "bXYe" {
+		t.Errorf("ReadAt(1) = %d, %v, %q", n, err, b)
+	}
+	if n, err := w.ReadAt(b, 5); err != io.EOF || n != 2 {
+		t.Errorf("ReadAt(5) = %d, %v", n, err)
+	}
+}
+
+// TestSyncWriterConcurrent is meant to be run with the race detector.
+func TestSyncWriterConcurrent(t *testing.T) {
+	const (
+		writers = 4
+		readers = 4
+		iters   = 1000
+	)
+	s := NewSync(New())
+	var wg sync.WaitGroup
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunk := bytes.Repeat([]byte{byte(i)}, 16)
+			for j := 0; j < iters; j++ {
+				switch j % 3 {
+				case 0:
+					if _, err := s.Seek(0, io.SeekEnd); err != nil {
+						t.Error(err)
+					}
+					if _, err := s.Write(chunk); err != nil {
+						t.Error(err)
+					}
+				case 1:
+					if _, err := s.WriteAt(chunk[:4], 0); err != nil {
+						t.Error(err)
+					}
+				case 2:
+					s.Snapshot().Release()
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := make([]byte, 8)
+			for j := 0; j < iters; j++ {
+				size := s.Size()
+				if size < 8 {
+					continue
+				}
+				if _, err := s.ReadAt(b, size-8); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Every writer appends on iterations where j%3 == 0.
+	want := int64(writers * ((iters + 2) / 3) * 16)
+	if got := s.Size(); got != want {
+		t.Errorf("got size %d, want %d", got, want)
+	}
+}
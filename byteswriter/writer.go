@@ -1,7 +1,8 @@
 // Package byteswriter implements a WriteSeeker backed by a
 // dynamically expanding buffer. Concurrent writes and seeks
 // the same Writer are not safe, and the user is responsible
-// for ensuring this.
+// for ensuring this, either directly or by wrapping it in a
+// SyncWriter.
 package byteswriter
 
 import (
@@ -12,8 +13,10 @@ import (
 // A Writer implements a WriteSeeker interface backed by a
 // dynamically expanding buffer.
 type Writer struct {
-	buf []byte
-	pos int
+	buf   []byte
+	pos   int
+	snaps []*Snapshot
+	crc   *crcTracker
 }
 
 // New returns a new writer with an initial allocation of 4KB.
@@ -37,15 +40,29 @@ func (w *Writer) Size() int64 {
 
 // Seek seeks to a given offset in a buffer.
 func (w *Writer) Seek(offset int64, whence int) (int64, error) {
+	off, err := seekOffset(int64(w.pos), int64(len(w.buf)), offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	w.pos = int(off)
+
+	return off, nil
+}
+
+// seekOffset returns the position resulting from seeking by offset from
+// whence, given the current position and size of a buffer. Note that offsets
+// relative to io.SeekEnd count backwards from the end of the buffer.
+func seekOffset(pos, size, offset int64, whence int) (int64, error) {
 	var off int64
 
 	switch whence {
 	case io.SeekCurrent:
-		off = offset + int64(w.pos)
+		off = offset + pos
 	case io.SeekStart:
 		off = offset
 	case io.SeekEnd:
-		off = int64(len(w.buf)) - offset
+		off = size - offset
 	default:
 		return 0, fmt.Errorf("invalid whence")
 	}
@@ -54,13 +71,16 @@ func (w *Writer) Seek(offset int64, whence int) (int64, error) {
 		return 0, fmt.Errorf("cannot seek before start of buffer")
 	}
 
-	w.pos = int(off)
-
 	return off, nil
 }
 
 // Write writes to the underlying buffer and increases size as necessary.
 func (w *Writer) Write(buf []byte) (int, error) {
+	if w.pos < len(w.buf) && len(w.snaps) > 0 {
+		w.preserve(w.pos, w.pos+len(buf))
+	}
+
+	oldCap := cap(w.buf)
 	if w.pos > len(w.buf) {
 		return 0, fmt.Errorf("Cannot write while past end of buffer.")
 	} else if w.pos == len(w.buf) {
@@ -71,10 +91,46 @@ func (w *Writer) Write(buf []byte) (int, error) {
 		overlap := copy(w.buf[w.pos:], buf)
 		w.buf = append(w.buf, buf[overlap:]...)
 	}
+	if cap(w.buf) != oldCap {
+		// The buffer was reallocated, so snapshots no longer share it.
+		w.snaps = nil
+	}
+	if w.crc != nil {
+		w.crc.invalidate(w.pos, w.pos+len(buf))
+	}
 	w.pos += len(buf)
 	return len(buf), nil
 }
 
+// WriteAt writes to the buffer at offset off without changing the seek
+// position. As with Write, off must not be past the end of the buffer.
+func (w *Writer) WriteAt(buf []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("cannot write before start of buffer")
+	}
+	pos := w.pos
+	w.pos = int(off)
+	n, err := w.Write(buf)
+	w.pos = pos
+	return n, err
+}
+
+// ReadAt reads from the buffer at offset off. It does not change the seek
+// position.
+func (w *Writer) ReadAt(buf []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("cannot read before start of buffer")
+	}
+	if off >= int64(len(w.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(buf, w.buf[off:])
+	if n < len(buf) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
 // Bytes returns the underlying byte buffer. The slice is valid for use only
 // until the next write. The slice aliases the buffer content, so changes to
 // the slice will affect the content of the writer itself.
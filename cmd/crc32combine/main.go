@@ -0,0 +1,203 @@
+// Command crc32combine combines the CRC-32 checksums of the chunks of a file,
+// as listed in a manifest, and optionally verifies the result against the
+// file itself.
+//
+// Usage:
+//
+//	crc32combine [-poly ieee|castagnoli|koopman] [-file path] [-j n] [manifest]
+//
+// The manifest is read from standard input if no path is given. Each line
+// holds the offset, length and checksum of a chunk, separated by whitespace.
+// Checksums may be decimal, hexadecimal with a 0x prefix, or big-endian
+// base64. Blank lines and lines starting with # are ignored. The chunks must
+// be listed in order and be contiguous, starting at offset 0.
+//
+// The exit code is 0 on success, 2 if the file does not match the manifest,
+// 64 (EX_USAGE) for invalid arguments, and 1 for any other error.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vimeo/go-util/crc32combine"
+	"github.com/vimeo/go-util/exit"
+)
+
+var polys = map[string]uint32{
+	"ieee":       crc32.IEEE,
+	"castagnoli": crc32.Castagnoli,
+	"koopman":    crc32.Koopman,
+}
+
+// errMismatch is returned by run when the file does not match the manifest.
+var errMismatch = errors.New("checksum mismatch")
+
+// mismatchCode is the exit code for errMismatch.
+const mismatchCode = 2
+
+type chunk struct {
+	offset int64
+	length int64
+	crc    uint32
+}
+
+// parseCRC parses a checksum in hexadecimal with a 0x prefix, decimal, or
+// base64. Decimal checksums may be zero-padded.
+func parseCRC(s string) (uint32, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		if v, err := strconv.ParseUint(s[2:], 16, 32); err == nil {
+			return uint32(v), nil
+		}
+	} else if v, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(v), nil
+	}
+	// base64 checksums may also start with 0x, such as "0x0x0w==".
+	return crc32combine.DecodeBase64(s)
+}
+
+// parseManifest reads the chunks listed in r, checking that they are
+// contiguous.
+func parseManifest(r io.Reader) ([]chunk, error) {
+	var chunks []chunk
+	var end int64
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected offset, length and crc", line)
+		}
+
+		var c chunk
+		var err error
+		if c.offset, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid offset: %v", line, err)
+		}
+		if c.length, err = strconv.ParseInt(fields[1], 10, 64); err != nil || c.length < 0 {
+			return nil, fmt.Errorf("line %d: invalid length %q", line, fields[1])
+		}
+		if c.crc, err = parseCRC(fields[2]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid crc %q", line, fields[2])
+		}
+		if c.offset != end {
+			return nil, fmt.Errorf("line %d: chunk starts at %d, expected %d", line, c.offset, end)
+		}
+		end += c.length
+		chunks = append(chunks, c)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, errors.New("empty manifest")
+	}
+	return chunks, nil
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("crc32combine", flag.ContinueOnError)
+	polyName := fs.String("poly", "castagnoli", "CRC-32 polynomial: ieee, castagnoli or koopman")
+	file := fs.String("file", "", "file to verify the combined checksum against")
+	jobs := fs.Int("j", 0, "number of goroutines hashing the file (default GOMAXPROCS)")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return err
+	} else if err != nil {
+		return exit.WithCode(err, exit.Usage)
+	}
+
+	poly, ok := polys[strings.ToLower(*polyName)]
+	if !ok {
+		return exit.WithCode(fmt.Errorf("unknown polynomial %q", *polyName), exit.Usage)
+	}
+
+	manifest := stdin
+	if fs.NArg() > 1 {
+		return exit.WithCode(errors.New("too many arguments"), exit.Usage)
+	} else if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		manifest = f
+	}
+
+	chunks, err := parseManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	comb := crc32combine.NewCombiner(poly)
+	crc := chunks[0].crc
+	size := chunks[0].length
+	for _, c := range chunks[1:] {
+		crc = comb.Combine(crc, c.crc, c.length)
+		size += c.length
+	}
+	fmt.Fprintf(stdout, "hex:     %08x\n", crc)
+	fmt.Fprintf(stdout, "decimal: %d\n", crc)
+	fmt.Fprintf(stdout, "base64:  %s\n", crc32combine.EncodeBase64(crc))
+
+	if *file == "" {
+		return nil
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() != size {
+		fmt.Fprintf(stdout, "file:    %d bytes, manifest covers %d\n", fi.Size(), size)
+		return errMismatch
+	}
+	fileCRC, err := crc32combine.ChecksumReaderAt(f, size, crc32.MakeTable(poly), *jobs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "file:    %08x\n", fileCRC)
+	if fileCRC != crc {
+		return errMismatch
+	}
+	return nil
+}
+
+// errorCode returns the exit code for an error returned by run.
+func errorCode(err error) int {
+	var e *exit.Error
+	switch {
+	case err == nil || err == flag.ErrHelp:
+		return 0
+	case err == errMismatch:
+		return mismatchCode
+	case errors.As(err, &e):
+		return e.Code
+	default:
+		return 1
+	}
+}
+
+func main() {
+	defer exit.Recover()
+
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil && err != flag.ErrHelp {
+		fmt.Fprintf(os.Stderr, "crc32combine: %v\n", err)
+	}
+	exit.Return(errorCode(err))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vimeo/go-util/crc32combine"
+	"github.com/vimeo/go-util/exit"
+)
+
+func TestParseCRC(t *testing.T) {
+	for s, want := range map[string]uint32{
+		"123":        123,
+		"0123":       123,
+		"0x0123":     0x123,
+		"0XDEADBEEF": 0xdeadbeef,
+		"4waSgw==":   0xe3069283,
+		"0x0x0w==":   0xd31d31d3,
+	} {
+		if got, err := parseCRC(s); err != nil || got != want {
+			t.Errorf("parseCRC(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"0x", "0xg", "4294967296"} {
+		if _, err := parseCRC(s); err == nil {
+			t.Errorf("parseCRC(%q) should fail", s)
+		}
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		manifest string
+		ok       bool
+	}{
+		{"decimal", "0 10 12345\n10 5 678\n", true},
+		{"zero-padded decimal", "0 10 0123\n", true},
+		{"hex and base64 with comments", "# chunks\n0 10 0xdeadbeef\n\n10 5 4waSgw==\n", true},
+		{"gap", "0 10 1\n11 5 2\n", false},
+		{"overlap", "0 10 1\n9 5 2\n", false},
+		{"not starting at zero", "5 10 1\n", false},
+		{"missing field", "0 10\n", false},
+		{"bad crc", "0 10 0x100000000\n", false},
+		{"negative length", "0 -1 1\n", false},
+		{"empty", "# nothing\n", false},
+	} {
+		chunks, err := parseManifest(strings.NewReader(tc.manifest))
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got error %v", tc.desc, err)
+		}
+		if tc.desc == "zero-padded decimal" && err == nil && chunks[0].crc != 123 {
+			t.Errorf("%s: got crc %d, want 123", tc.desc, chunks[0].crc)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	rng := rand.New(rand.NewSource(40))
+	data := make([]byte, 10000)
+	rng.Read(data)
+
+	f, err := ioutil.TempFile("", "crc32combine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(data)
+	f.Close()
+
+	tab := crc32.MakeTable(crc32.Koopman)
+	var manifest strings.Builder
+	for off := 0; off < len(data); off += 3000 {
+		end := off + 3000
+		if end > len(data) {
+			end = len(data)
+		}
+		fmt.Fprintf(&manifest, "%d %d %#x\n", off, end-off, crc32.Checksum(data[off:end], tab))
+	}
+
+	var out bytes.Buffer
+	err = run([]string{"-poly", "koopman", "-file", f.Name(), "-j", "2"}, strings.NewReader(manifest.String()), &out)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	want := crc32.Checksum(data, tab)
+	for _, s := range []string{fmt.Sprintf("%08x", want), fmt.Sprint(want), crc32combine.EncodeBase64(want)} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output does not contain %q:\n%s", s, out.String())
+		}
+	}
+
+	// The same manifest does not match with another polynomial.
+	out.Reset()
+	err = run([]string{"-poly", "ieee", "-file", f.Name()}, strings.NewReader(manifest.String()), &out)
+	if err != errMismatch {
+		t.Errorf("got %v, want %v", err, errMismatch)
+	}
+
+	if code := errorCode(err); code != mismatchCode {
+		t.Errorf("mismatch: got exit code %d, want %d", code, mismatchCode)
+	}
+
+	for _, args := range [][]string{
+		{"-poly", "crc64"},
+		{"-unknown"},
+		{"-j", "many"},
+		{"a", "b"},
+	} {
+		err := run(args, strings.NewReader(manifest.String()), ioutil.Discard)
+		if code := errorCode(err); code != exit.Usage {
+			t.Errorf("%q: got exit code %d (%v), want %d", args, code, err, exit.Usage)
+		}
+	}
+	if code := errorCode(run([]string{"-h"}, strings.NewReader(""), ioutil.Discard)); code != 0 {
+		t.Errorf("-h: got exit code %d, want 0", code)
+	}
+	if code := errorCode(run(nil, strings.NewReader("bad"), ioutil.Discard)); code != 1 {
+		t.Errorf("bad manifest: got exit code %d, want 1", code)
+	}
+}
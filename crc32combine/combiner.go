@@ -0,0 +1,120 @@
+package crc32combine
+
+// gf2Matrix is a square matrix over GF(2) operating on vectors of up to 64
+// bits. Row n is the image of bit n, so the width of the matrix is its
+// length.
+type gf2Matrix []uint64
+
+func (mat gf2Matrix) times(vec uint64) uint64 {
+	var sum uint64
+
+	for n := 0; vec != 0; n++ {
+		if vec&1 != 0 {
+			sum ^= mat[n]
+		}
+		vec >>= 1
+	}
+	return sum
+}
+
+// square returns mat * mat.
+func (mat gf2Matrix) square() gf2Matrix {
+	square := make(gf2Matrix, len(mat))
+	for n := range mat {
+		square[n] = mat.times(mat[n])
+	}
+	return square
+}
+
+// reflectedBitOp returns the operator that feeds one zero bit into a
+// reflected CRC register of the given width with generator polynomial poly.
+func reflectedBitOp(poly uint64, width int) gf2Matrix {
+	op := make(gf2Matrix, width)
+	op[0] = poly
+	row := uint64(1)
+	for n := 1; n < width; n++ {
+		op[n] = row
+		row <<= 1
+	}
+	return op
+}
+
+// reflectedInverseBitOp returns the inverse of reflectedBitOp, which removes
+// one zero bit from a reflected CRC register. The top bit of the register
+// tells whether poly was added when the bit was fed in, which requires the
+// x^0 term of the polynomial, stored in the top bit of poly, to be set.
+func reflectedInverseBitOp(poly uint64, width int) gf2Matrix {
+	op := make(gf2Matrix, width)
+	mask := uint64(1)<<uint(width-1)<<1 - 1
+	for n := 0; n < width-1; n++ {
+		op[n] = 1 << uint(n+1)
+	}
+	op[width-1] = ((1<<uint(width-1)^poly)<<1 | 1) & mask
+	return op
+}
+
+// zeroOps holds the operators that feed 2^k zero bytes into a CRC register,
+// for every k for which 2^k fits in an int64.
+type zeroOps [63]gf2Matrix
+
+// newZeroOps builds the zero-byte operators from the operator for a single
+// zero bit.
+func newZeroOps(bitOp gf2Matrix) *zeroOps {
+	z := new(zeroOps)
+	z[0] = bitOp.square().square().square()
+	for k := 1; k < len(z); k++ {
+		z[k] = z[k-1].square()
+	}
+	return z
+}
+
+// apply feeds n zero bytes into the register crc. It does not allocate.
+func (z *zeroOps) apply(crc uint64, n int64) uint64 {
+	for k := 0; n > 0; k++ {
+		if n&1 != 0 {
+			crc = z[k].times(crc)
+		}
+		n >>= 1
+	}
+	return crc
+}
+
+// shiftZeros feeds n zero bytes into the register crc, squaring the
+// operators only as far as n requires. It is meant for one-off use, where
+// building a zeroOps would be wasteful.
+func shiftZeros(bitOp gf2Matrix, crc uint64, n int64) uint64 {
+	op := bitOp.square().square().square()
+	for n > 0 {
+		if n&1 != 0 {
+			crc = op.times(crc)
+		}
+		n >>= 1
+		if n > 0 {
+			op = op.square()
+		}
+	}
+	return crc
+}
+
+// A Combiner combines CRC-32 hash values for a single generator polynomial.
+// It computes the operators CRC32Combine needs once, so that each call to
+// Combine only takes O(log len2) matrix multiplications and does not
+// allocate. A Combiner is safe for concurrent use.
+type Combiner struct {
+	ops *zeroOps
+}
+
+// NewCombiner returns a Combiner for the generator polynomial poly, in the
+// reversed notation used by hash/crc32.
+func NewCombiner(poly uint32) *Combiner {
+	return &Combiner{ops: newZeroOps(reflectedBitOp(uint64(poly), 32))}
+}
+
+// Combine returns the combined CRC-32 hash value of crc1 and crc2, where len2
+// is the byte length that crc2 covers. It is equivalent to CRC32Combine.
+func (c *Combiner) Combine(crc1, crc2 uint32, len2 int64) uint32 {
+	if len2 <= 0 {
+		return crc1
+	}
+	return uint32(c.ops.apply(uint64(crc1), len2)) ^ crc2
+}
@@ -0,0 +1,47 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestCombiner(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		c := NewCombiner(poly)
+		for i := 0; i < 1000; i++ {
+			crc1, crc2 := rng.Uint32(), rng.Uint32()
+			len2 := rng.Int63n(1 << uint(rng.Intn(63)))
+			if got, want := c.Combine(crc1, crc2, len2), CRC32Combine(poly, crc1, crc2, len2); got != want {
+				t.Fatalf("poly %#x: Combine(%#x, %#x, %d) = %#x, want %#x", poly, crc1, crc2, len2, got, want)
+			}
+		}
+	}
+}
+
+func TestCombinerAllocs(t *testing.T) {
+	c := NewCombiner(crc32.Castagnoli)
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Combine(683702737, 3632182834, 23801619)
+	})
+	if allocs != 0 {
+		t.Errorf("Combine allocated %v times", allocs)
+	}
+}
+
+func BenchmarkCRC32Combine(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CRC32Combine(crc32.Castagnoli, 683702737, 3632182834, 23801619)
+	}
+}
+
+func BenchmarkCombiner(b *testing.B) {
+	c := NewCombiner(crc32.Castagnoli)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Combine(683702737, 3632182834, 23801619)
+	}
+}
@@ -0,0 +1,82 @@
+package crc32combine
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// ErrNoCRC32C is returned by GoogHashCRC32C when the header has no CRC32C
+// value.
+var ErrNoCRC32C = errors.New("crc32combine: no crc32c in x-goog-hash")
+
+// A Part is the checksum of one part of a multipart or composed object,
+// along with the byte length of the part.
+type Part struct {
+	CRC    uint32
+	Length int64
+}
+
+// CompositeCRC32C returns the CRC32C of a whole object given the CRC32C
+// values of its parts, in order. This is the value object stores report for
+// composed objects, or as a full object checksum for multipart uploads.
+func CompositeCRC32C(parts []Part) uint32 {
+	if len(parts) == 0 {
+		return 0
+	}
+	comb := NewCombiner(crc32.Castagnoli)
+	crc := parts[0].CRC
+	for _, p := range parts[1:] {
+		crc = comb.Combine(crc, p.CRC, p.Length)
+	}
+	return crc
+}
+
+// EncodeBase64 returns the base64 encoding of the big-endian representation
+// of crc, as used by the x-goog-hash header and S3 checksum headers.
+func EncodeBase64(crc uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], crc)
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+// DecodeBase64 decodes a checksum encoded by EncodeBase64.
+func DecodeBase64(s string) (uint32, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 4 {
+		return 0, fmt.Errorf("crc32combine: decoded checksum has %d bytes, want 4", len(b))
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// GoogHashCRC32C returns the CRC32C found in the value of an x-goog-hash
+// header, such as "crc32c=n03x6A==,md5=Ojk9c3dhfxgoKVVHYwFbHQ==". It returns
+// ErrNoCRC32C if there is none.
+func GoogHashCRC32C(value string) (uint32, error) {
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "crc32c=") {
+			return DecodeBase64(field[len("crc32c="):])
+		}
+	}
+	return 0, ErrNoCRC32C
+}
+
+// ChecksumOfChecksums returns the composite checksum S3 reports for a
+// multipart upload: the checksum of the concatenated big-endian part
+// checksums using the given table, encoded in base64 and followed by a dash
+// and the number of parts.
+func ChecksumOfChecksums(tab *crc32.Table, parts []uint32) string {
+	b := make([]byte, 0, 4*len(parts))
+	for _, crc := range parts {
+		b = appendUint32(b, crc)
+	}
+	return EncodeBase64(crc32.Checksum(b, tab)) + "-" + strconv.Itoa(len(parts))
+}
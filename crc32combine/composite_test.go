@@ -0,0 +1,65 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestCompositeCRC32C(t *testing.T) {
+	rng := rand.New(rand.NewSource(37))
+	data := make([]byte, 100000)
+	rng.Read(data)
+	tab := crc32.MakeTable(crc32.Castagnoli)
+
+	var parts []Part
+	for off := 0; off < len(data); {
+		end := off + 1 + rng.Intn(20000)
+		if end > len(data) {
+			end = len(data)
+		}
+		parts = append(parts, Part{CRC: crc32.Checksum(data[off:end], tab), Length: int64(end - off)})
+		off = end
+	}
+
+	if got, want := CompositeCRC32C(parts), crc32.Checksum(data, tab); got != want {
+		t.Errorf("got %#08x, want %#08x", got, want)
+	}
+	if got := CompositeCRC32C(nil); got != 0 {
+		t.Errorf("got %#08x for no parts", got)
+	}
+}
+
+func TestBase64(t *testing.T) {
+	// CRC32C of "123456789".
+	if got := EncodeBase64(0xe3069283); got != "4waSgw==" {
+		t.Errorf("EncodeBase64 = %q", got)
+	}
+	if got, err := DecodeBase64("4waSgw=="); err != nil || got != 0xe3069283 {
+		t.Errorf("DecodeBase64 = %#08x, %v", got, err)
+	}
+	for _, s := range []string{"4waSgw", "4waSg4Mw", "not base64!"} {
+		if _, err := DecodeBase64(s); err == nil {
+			t.Errorf("DecodeBase64(%q) should fail", s)
+		}
+	}
+}
+
+func TestGoogHashCRC32C(t *testing.T) {
+	crc, err := GoogHashCRC32C("md5=Ojk9c3dhfxgoKVVHYwFbHQ==, crc32c=4waSgw==")
+	if err != nil || crc != 0xe3069283 {
+		t.Errorf("got %#08x, %v", crc, err)
+	}
+	if _, err := GoogHashCRC32C("md5=Ojk9c3dhfxgoKVVHYwFbHQ=="); err != ErrNoCRC32C {
+		t.Errorf("got %v, want %v", err, ErrNoCRC32C)
+	}
+}
+
+func TestChecksumOfChecksums(t *testing.T) {
+	tab := crc32.MakeTable(crc32.Castagnoli)
+	parts := []uint32{0x01020304, 0xa0b0c0d0}
+	want := EncodeBase64(crc32.Checksum([]byte{1, 2, 3, 4, 0xa0, 0xb0, 0xc0, 0xd0}, tab)) + "-2"
+	if got := ChecksumOfChecksums(tab, parts); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
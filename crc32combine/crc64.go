@@ -0,0 +1,34 @@
+package crc32combine
+
+// CRC64Combine returns the combined CRC-64 hash value of the two passed
+// CRC-64 hash values crc1 and crc2, as computed by hash/crc64. poly
+// represents the generator polynomial, such as crc64.ISO or crc64.ECMA, and
+// len2 specifies the byte length that the crc2 hash covers. For a table
+// returned by crc64.MakeTable(poly), poly is also found at index 0x80.
+func CRC64Combine(poly uint64, crc1, crc2 uint64, len2 int64) uint64 {
+	// degenerate case (also disallow negative lengths)
+	if len2 <= 0 {
+		return crc1
+	}
+	return shiftZeros(reflectedBitOp(poly, 64), crc1, len2) ^ crc2
+}
+
+// A Combiner64 is the CRC-64 counterpart of Combiner.
+type Combiner64 struct {
+	ops *zeroOps
+}
+
+// NewCombiner64 returns a Combiner64 for the generator polynomial poly, as
+// passed to crc64.MakeTable.
+func NewCombiner64(poly uint64) *Combiner64 {
+	return &Combiner64{ops: newZeroOps(reflectedBitOp(poly, 64))}
+}
+
+// Combine returns the combined CRC-64 hash value of crc1 and crc2, where len2
+// is the byte length that crc2 covers. It is equivalent to CRC64Combine.
+func (c *Combiner64) Combine(crc1, crc2 uint64, len2 int64) uint64 {
+	if len2 <= 0 {
+		return crc1
+	}
+	return c.ops.apply(crc1, len2) ^ crc2
+}
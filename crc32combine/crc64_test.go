@@ -0,0 +1,36 @@
+package crc32combine
+
+import (
+	"hash/crc64"
+	"math/rand"
+	"testing"
+)
+
+func TestCRC64Combine(t *testing.T) {
+	rng := rand.New(rand.NewSource(64))
+	data := make([]byte, 1<<16)
+	rng.Read(data)
+
+	for _, poly := range []uint64{crc64.ISO, crc64.ECMA} {
+		tab := crc64.MakeTable(poly)
+		if tab[0x80] != poly {
+			t.Errorf("table for %#x has %#x at index 0x80", poly, tab[0x80])
+		}
+		c := NewCombiner64(poly)
+		want := crc64.Checksum(data, tab)
+
+		for i := 0; i < 100; i++ {
+			split := rng.Intn(len(data) + 1)
+			crc1 := crc64.Checksum(data[:split], tab)
+			crc2 := crc64.Checksum(data[split:], tab)
+			len2 := int64(len(data) - split)
+
+			if got := CRC64Combine(poly, crc1, crc2, len2); got != want {
+				t.Fatalf("poly %#x, split %d: CRC64Combine = %#x, want %#x", poly, split, got, want)
+			}
+			if got := c.Combine(crc1, crc2, len2); got != want {
+				t.Fatalf("poly %#x, split %d: Combine = %#x, want %#x", poly, split, got, want)
+			}
+		}
+	}
+}
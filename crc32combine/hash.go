@@ -0,0 +1,151 @@
+package crc32combine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"sync"
+)
+
+const (
+	hashMagic         = "crcc\x01"
+	marshaledHashSize = len(hashMagic) + 4 + 4 + 8
+)
+
+// A Hash is a hash.Hash32 computing a CRC-32 checksum, whose state is the
+// checksum along with the number of bytes it covers. This allows the results
+// of several Hashes to be concatenated with Append, and the state to be
+// persisted with MarshalBinary and restored with UnmarshalBinary, for
+// example to checksum pieces of a file separately and compute the checksum of
+// the whole file from them. The zero Hash computes the IEEE checksum.
+type Hash struct {
+	tab *crc32.Table
+	crc uint32
+	n   int64
+}
+
+var (
+	_ hash.Hash32 = (*Hash)(nil)
+)
+
+// combiners caches the Combiner used by Append for each polynomial.
+var combiners sync.Map // uint32 -> *Combiner
+
+func combinerFor(poly uint32) *Combiner {
+	if c, ok := combiners.Load(poly); ok {
+		return c.(*Combiner)
+	}
+	c, _ := combiners.LoadOrStore(poly, NewCombiner(poly))
+	return c.(*Combiner)
+}
+
+// NewHash returns a new Hash computing the CRC-32 checksum using the given
+// table, as returned by crc32.MakeTable.
+func NewHash(tab *crc32.Table) *Hash {
+	return &Hash{tab: tab}
+}
+
+// table returns the table of h, which defaults to the IEEE table.
+func (h *Hash) table() *crc32.Table {
+	if h.tab == nil {
+		return crc32.IEEETable
+	}
+	return h.tab
+}
+
+// Write adds p to the running checksum. It never returns an error.
+func (h *Hash) Write(p []byte) (int, error) {
+	h.crc = crc32.Update(h.crc, h.table(), p)
+	h.n += int64(len(p))
+	return len(p), nil
+}
+
+// Sum appends the big-endian checksum to b.
+func (h *Hash) Sum(b []byte) []byte {
+	s := h.Sum32()
+	return append(b, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+// Sum32 returns the checksum.
+func (h *Hash) Sum32() uint32 {
+	return h.crc
+}
+
+// Len returns the number of bytes the checksum covers.
+func (h *Hash) Len() int64 {
+	return h.n
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Hash) Reset() {
+	h.crc = 0
+	h.n = 0
+}
+
+// Size returns the number of bytes Sum will append.
+func (h *Hash) Size() int {
+	return crc32.Size
+}
+
+// BlockSize returns the hash's underlying block size.
+func (h *Hash) BlockSize() int {
+	return 1
+}
+
+// Append updates h as if the bytes covered by other had been written to h
+// after the bytes already written. Both Hashes must use the same polynomial.
+func (h *Hash) Append(other *Hash) error {
+	poly := tablePoly(h.table())
+	if otherPoly := tablePoly(other.table()); otherPoly != poly {
+		return fmt.Errorf("cannot append CRC-32 with polynomial %#x to one with polynomial %#x",
+			otherPoly, poly)
+	}
+	h.crc = combinerFor(poly).Combine(h.crc, other.crc, other.n)
+	h.n += other.n
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoded state
+// includes the polynomial, the checksum and the length it covers.
+func (h *Hash) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledHashSize)
+	b = append(b, hashMagic...)
+	b = appendUint32(b, tablePoly(h.table()))
+	b = appendUint32(b, h.crc)
+	b = appendUint64(b, uint64(h.n))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores the
+// state saved by MarshalBinary, including the polynomial, so it may be
+// used on a zero Hash.
+func (h *Hash) UnmarshalBinary(b []byte) error {
+	if len(b) < len(hashMagic) || string(b[:len(hashMagic)]) != hashMagic {
+		return errors.New("crc32combine: invalid hash state identifier")
+	}
+	if len(b) != marshaledHashSize {
+		return errors.New("crc32combine: invalid hash state size")
+	}
+	b = b[len(hashMagic):]
+	poly := binary.BigEndian.Uint32(b)
+	if tablePoly(h.table()) != poly {
+		h.tab = crc32.MakeTable(poly)
+	}
+	h.crc = binary.BigEndian.Uint32(b[4:])
+	h.n = int64(binary.BigEndian.Uint64(b[8:]))
+	return nil
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	var a [4]byte
+	binary.BigEndian.PutUint32(a[:], x)
+	return append(b, a[:]...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var a [8]byte
+	binary.BigEndian.PutUint64(a[:], x)
+	return append(b, a[:]...)
+}
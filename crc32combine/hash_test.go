@@ -0,0 +1,103 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestHashAppend(t *testing.T) {
+	rng := rand.New(rand.NewSource(33))
+	data := make([]byte, 10000)
+	rng.Read(data)
+
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+
+		// Checksum the pieces separately, as distributed workers would.
+		var states [][]byte
+		for off := 0; off < len(data); off += 3000 {
+			end := off + 3000
+			if end > len(data) {
+				end = len(data)
+			}
+			h := NewHash(tab)
+			h.Write(data[off:end])
+			b, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			states = append(states, b)
+		}
+
+		var total Hash
+		if err := total.UnmarshalBinary(states[0]); err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range states[1:] {
+			var part Hash
+			if err := part.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if err := total.Append(&part); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got, want := total.Sum32(), crc32.Checksum(data, tab); got != want {
+			t.Errorf("poly %#x: got %#x, want %#x", poly, got, want)
+		}
+		if total.Len() != int64(len(data)) {
+			t.Errorf("poly %#x: got length %d, want %d", poly, total.Len(), len(data))
+		}
+
+		// Writing after Append continues the same checksum.
+		total.Write([]byte("tail"))
+		if got, want := total.Sum32(), crc32.Checksum(append(data, "tail"...), tab); got != want {
+			t.Errorf("poly %#x: got %#x after write, want %#x", poly, got, want)
+		}
+	}
+}
+
+func TestHashSum(t *testing.T) {
+	h := NewHash(crc32.IEEETable)
+	h.Write([]byte("123456789"))
+	if got := h.Sum(nil); string(got) != "\xcb\xf4\x39\x26" {
+		t.Errorf("got %x", got)
+	}
+	h.Reset()
+	if h.Sum32() != 0 || h.Len() != 0 {
+		t.Errorf("Reset did not clear state")
+	}
+}
+
+func TestHashZero(t *testing.T) {
+	var a, b Hash
+	a.Write([]byte("12345"))
+	b.Write([]byte("6789"))
+	if err := a.Append(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := a.Sum32(), crc32.ChecksumIEEE([]byte("123456789")); got != want {
+		t.Errorf("got %#x, want %#x", got, want)
+	}
+	if _, err := a.MarshalBinary(); err != nil {
+		t.Errorf("MarshalBinary: %v", err)
+	}
+}
+
+func TestHashErrors(t *testing.T) {
+	a := NewHash(crc32.IEEETable)
+	b := NewHash(crc32.MakeTable(crc32.Castagnoli))
+	if err := a.Append(b); err == nil {
+		t.Errorf("appending hashes with different polynomials should fail")
+	}
+
+	state, _ := a.MarshalBinary()
+	if err := a.UnmarshalBinary(state[:len(state)-1]); err == nil {
+		t.Errorf("unmarshaling truncated state should fail")
+	}
+	if err := a.UnmarshalBinary([]byte("garbage garbage garbage")); err == nil {
+		t.Errorf("unmarshaling garbage should fail")
+	}
+}
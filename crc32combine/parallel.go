@@ -0,0 +1,168 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// chunkSize is the size of the chunks hashed in parallel by ChecksumReaderAt
+// and ChecksumReader. It is a variable so that tests can use smaller chunks.
+var chunkSize = 4 * 1024 * 1024
+
+// tablePoly returns the generator polynomial a table was made from, which
+// is the entry for 0x80 of any table built by crc32.MakeTable.
+func tablePoly(tab *crc32.Table) uint32 {
+	return tab[0x80]
+}
+
+func defaultConcurrency(concurrency int) int {
+	if concurrency <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return concurrency
+}
+
+// ChecksumReaderAt returns the CRC-32 checksum of the first size bytes of r
+// using the given table. The input is divided into fixed-size chunks which are
+// read and hashed by up to concurrency goroutines, and the chunk checksums are
+// then combined. If concurrency is not positive, GOMAXPROCS is used.
+func ChecksumReaderAt(r io.ReaderAt, size int64, tab *crc32.Table, concurrency int) (uint32, error) {
+	if size <= 0 {
+		return 0, nil
+	}
+	concurrency = defaultConcurrency(concurrency)
+
+	chunks := int((size + int64(chunkSize) - 1) / int64(chunkSize))
+	if concurrency > chunks {
+		concurrency = chunks
+	}
+
+	crcs := make([]uint32, chunks)
+	next := make(chan int)
+	errs := make(chan error, concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, chunkSize)
+			for c := range next {
+				off := int64(c) * int64(chunkSize)
+				n := int64(chunkSize)
+				if off+n > size {
+					n = size - off
+				}
+				read, err := r.ReadAt(buf[:n], off)
+				if int64(read) == n {
+					err = nil
+				} else if err == nil || err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				crcs[c] = crc32.Checksum(buf[:n], tab)
+			}
+		}()
+	}
+
+	var err error
+	for c := 0; c < chunks && err == nil; c++ {
+		select {
+		case next <- c:
+		case err = <-errs:
+		}
+	}
+	close(next)
+	wg.Wait()
+	if err != nil {
+		return 0, err
+	}
+	select {
+	case err = <-errs:
+		return 0, err
+	default:
+	}
+
+	comb := NewCombiner(tablePoly(tab))
+	crc := crcs[0]
+	for c := 1; c < chunks; c++ {
+		n := int64(chunkSize)
+		if c == chunks-1 {
+			n = size - int64(c)*int64(chunkSize)
+		}
+		crc = comb.Combine(crc, crcs[c], n)
+	}
+	return crc, nil
+}
+
+type chunkResult struct {
+	crc uint32
+	n   int
+}
+
+// ChecksumReader returns the CRC-32 checksum of everything read from r until
+// EOF using the given table, along with the number of bytes read. Chunks are
+// read sequentially and hashed by separate goroutines, with at most
+// concurrency chunks read ahead of the ones being combined. If concurrency is
+// not positive, GOMAXPROCS is used.
+func ChecksumReader(r io.Reader, tab *crc32.Table, concurrency int) (uint32, int64, error) {
+	concurrency = defaultConcurrency(concurrency)
+
+	free := make(chan []byte, concurrency)
+	for i := 0; i < concurrency; i++ {
+		free <- make([]byte, chunkSize)
+	}
+	pending := make(chan chan chunkResult, concurrency)
+
+	type total struct {
+		crc uint32
+		n   int64
+	}
+	done := make(chan total)
+	go func() {
+		comb := NewCombiner(tablePoly(tab))
+		var t total
+		for res := range pending {
+			cr := <-res
+			t.crc = comb.Combine(t.crc, cr.crc, int64(cr.n))
+			t.n += int64(cr.n)
+		}
+		done <- t
+	}()
+
+	var err error
+	for {
+		buf := <-free
+		var n int
+		n, err = io.ReadFull(r, buf)
+		if n == 0 {
+			break
+		}
+
+		res := make(chan chunkResult, 1)
+		pending <- res
+		go func(buf []byte, n int) {
+			res <- chunkResult{crc: crc32.Checksum(buf[:n], tab), n: n}
+			free <- buf
+		}(buf, n)
+
+		if err != nil {
+			break
+		}
+	}
+	close(pending)
+	t := <-done
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if err != nil {
+		return 0, t.n, err
+	}
+	return t.crc, t.n, nil
+}
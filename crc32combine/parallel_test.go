@@ -0,0 +1,90 @@
+package crc32combine
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+func withChunkSize(n int) func() {
+	old := chunkSize
+	chunkSize = n
+	return func() { chunkSize = old }
+}
+
+func TestChecksumReaderAt(t *testing.T) {
+	defer withChunkSize(1000)()
+
+	rng := rand.New(rand.NewSource(32))
+	data := make([]byte, 25*1000+17)
+	rng.Read(data)
+
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		for _, size := range []int{0, 1, 999, 1000, 1001, len(data)} {
+			want := crc32.Checksum(data[:size], tab)
+			for _, conc := range []int{0, 1, 3, 64} {
+				got, err := ChecksumReaderAt(bytes.NewReader(data), int64(size), tab, conc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("poly %#x, size %d, concurrency %d: got %#x, want %#x", poly, size, conc, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestChecksumReaderAtShort(t *testing.T) {
+	defer withChunkSize(10)()
+
+	_, err := ChecksumReaderAt(bytes.NewReader(make([]byte, 95)), 100, crc32.IEEETable, 4)
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestChecksumReader(t *testing.T) {
+	defer withChunkSize(1000)()
+
+	rng := rand.New(rand.NewSource(33))
+	data := make([]byte, 25*1000+17)
+	rng.Read(data)
+	tab := crc32.MakeTable(crc32.Castagnoli)
+
+	for _, size := range []int{0, 1, 1000, 1001, len(data)} {
+		want := crc32.Checksum(data[:size], tab)
+		for _, conc := range []int{0, 1, 3} {
+			// OneByteReader makes sure short reads are handled.
+			got, n, err := ChecksumReader(iotest.OneByteReader(bytes.NewReader(data[:size])), tab, conc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(size) {
+				t.Errorf("size %d, concurrency %d: read %d bytes", size, conc, n)
+			}
+			if got != want {
+				t.Errorf("size %d, concurrency %d: got %#x, want %#x", size, conc, got, want)
+			}
+		}
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestChecksumReaderError(t *testing.T) {
+	defer withChunkSize(10)()
+
+	errTest := errors.New("test error")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 25)), errReader{errTest})
+	if _, _, err := ChecksumReader(r, crc32.IEEETable, 2); err != errTest {
+		t.Errorf("got %v, want %v", err, errTest)
+	}
+}
@@ -0,0 +1,166 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/bits"
+	"sync"
+)
+
+// Params describes a CRC-32 algorithm in the Rocksoft model. CRC32Combine
+// and the other functions of this package taking a polynomial only apply to
+// algorithms like CRC-32/ISO-HDLC, which are reflected and use 0xFFFFFFFF for
+// both Init and XorOut; Params covers all the others as well.
+type Params struct {
+	// Name is the name of the algorithm in the CRC RevEng catalogue.
+	Name string
+	// Poly is the generator polynomial in normal notation, with the x^32
+	// term omitted, e.g. 0x04C11DB7.
+	Poly uint32
+	// Init is the initial value of the register.
+	Init uint32
+	// RefIn indicates whether each input byte is processed least
+	// significant bit first.
+	RefIn bool
+	// RefOut indicates whether the register is reflected before XorOut is
+	// applied.
+	RefOut bool
+	// XorOut is XORed with the register to produce the final value.
+	XorOut uint32
+	// Check is the checksum of the ASCII string "123456789".
+	Check uint32
+}
+
+// Standard CRC-32 algorithms, as listed in the CRC RevEng catalogue.
+var (
+	AIXM     = Params{Name: "CRC-32/AIXM", Poly: 0x814141ab, Init: 0x00000000, RefIn: false, RefOut: false, XorOut: 0x00000000, Check: 0x3010bf7f}
+	AUTOSAR  = Params{Name: "CRC-32/AUTOSAR", Poly: 0xf4acfb13, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0x1697d06a}
+	BASE91D  = Params{Name: "CRC-32/BASE91-D", Poly: 0xa833982b, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0x87315576}
+	BZIP2    = Params{Name: "CRC-32/BZIP2", Poly: 0x04c11db7, Init: 0xffffffff, RefIn: false, RefOut: false, XorOut: 0xffffffff, Check: 0xfc891918}
+	CDROMEDC = Params{Name: "CRC-32/CD-ROM-EDC", Poly: 0x8001801b, Init: 0x00000000, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0x6ec2edc4}
+	CKSUM    = Params{Name: "CRC-32/CKSUM", Poly: 0x04c11db7, Init: 0x00000000, RefIn: false, RefOut: false, XorOut: 0xffffffff, Check: 0x765e7680}
+	ISCSI    = Params{Name: "CRC-32/ISCSI", Poly: 0x1edc6f41, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xe3069283}
+	ISOHDLC  = Params{Name: "CRC-32/ISO-HDLC", Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xcbf43926}
+	JAMCRC   = Params{Name: "CRC-32/JAMCRC", Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0x340bc6d9}
+	MEF      = Params{Name: "CRC-32/MEF", Poly: 0x741b8cd7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0xd2c22f51}
+	MPEG2    = Params{Name: "CRC-32/MPEG-2", Poly: 0x04c11db7, Init: 0xffffffff, RefIn: false, RefOut: false, XorOut: 0x00000000, Check: 0x0376e6e7}
+	XFER     = Params{Name: "CRC-32/XFER", Poly: 0x000000af, Init: 0x00000000, RefIn: false, RefOut: false, XorOut: 0x00000000, Check: 0xbd0be338}
+)
+
+// Catalogue lists the standard CRC-32 algorithms defined by this package.
+var Catalogue = []Params{
+	AIXM, AUTOSAR, BASE91D, BZIP2, CDROMEDC, CKSUM, ISCSI, ISOHDLC, JAMCRC, MEF, MPEG2, XFER,
+}
+
+// Lookup returns the algorithm in Catalogue with the given name.
+func Lookup(name string) (Params, bool) {
+	for _, p := range Catalogue {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Params{}, false
+}
+
+// model holds the tables and operators derived from a Params.
+type model struct {
+	// reflected is used when RefIn is set, and normal otherwise.
+	reflected *crc32.Table
+	normal    [256]uint32
+	ops       *zeroOps
+}
+
+var models sync.Map // Params -> *model
+
+func (p Params) model() *model {
+	if m, ok := models.Load(p); ok {
+		return m.(*model)
+	}
+
+	m := new(model)
+	if p.RefIn {
+		rpoly := bits.Reverse32(p.Poly)
+		m.reflected = crc32.MakeTable(rpoly)
+		m.ops = newZeroOps(reflectedBitOp(uint64(rpoly), 32))
+	} else {
+		for i := range m.normal {
+			crc := uint32(i) << 24
+			for j := 0; j < 8; j++ {
+				if crc&0x80000000 != 0 {
+					crc = crc<<1 ^ p.Poly
+				} else {
+					crc <<= 1
+				}
+			}
+			m.normal[i] = crc
+		}
+		m.ops = newZeroOps(normalBitOp(uint64(p.Poly), 32))
+	}
+
+	actual, _ := models.LoadOrStore(p, m)
+	return actual.(*model)
+}
+
+// normalBitOp returns the operator that feeds one zero bit into a
+// non-reflected CRC register of the given width with generator polynomial
+// poly.
+func normalBitOp(poly uint64, width int) gf2Matrix {
+	op := make(gf2Matrix, width)
+	for n := 0; n < width-1; n++ {
+		op[n] = 1 << uint(n+1)
+	}
+	op[width-1] = poly
+	return op
+}
+
+// initReg returns the initial register value. The register is kept in
+// reflected form when RefIn is set.
+func (p Params) initReg() uint32 {
+	if p.RefIn {
+		return bits.Reverse32(p.Init)
+	}
+	return p.Init
+}
+
+// toReg returns the register value that produces crc.
+func (p Params) toReg(crc uint32) uint32 {
+	reg := crc ^ p.XorOut
+	if p.RefIn != p.RefOut {
+		reg = bits.Reverse32(reg)
+	}
+	return reg
+}
+
+// fromReg returns the checksum produced by the register value reg.
+func (p Params) fromReg(reg uint32) uint32 {
+	if p.RefIn != p.RefOut {
+		reg = bits.Reverse32(reg)
+	}
+	return reg ^ p.XorOut
+}
+
+// Checksum returns the checksum of data.
+func (p Params) Checksum(data []byte) uint32 {
+	m := p.model()
+	reg := p.initReg()
+	if p.RefIn {
+		// crc32.Update complements the register on the way in and out.
+		reg = ^crc32.Update(^reg, m.reflected, data)
+	} else {
+		for _, b := range data {
+			reg = reg<<8 ^ m.normal[byte(reg>>24)^b]
+		}
+	}
+	return p.fromReg(reg)
+}
+
+// Combine returns the combined checksum of crc1 and crc2, where len2 is the
+// byte length that crc2 covers.
+func (p Params) Combine(crc1, crc2 uint32, len2 int64) uint32 {
+	if len2 <= 0 {
+		return crc1
+	}
+	// Feeding B into the register left by A differs from feeding it into
+	// the initial register by the shift of their difference.
+	reg := p.model().ops.apply(uint64(p.toReg(crc1)^p.initReg()), len2)
+	return p.fromReg(uint32(reg) ^ p.toReg(crc2))
+}
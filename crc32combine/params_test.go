@@ -0,0 +1,60 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestParamsCheck(t *testing.T) {
+	for _, p := range Catalogue {
+		if got := p.Checksum([]byte("123456789")); got != p.Check {
+			t.Errorf("%s: got %#08x, want %#08x", p.Name, got, p.Check)
+		}
+	}
+}
+
+func TestParamsCombine(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	data := make([]byte, 5000)
+	rng.Read(data)
+
+	for _, p := range Catalogue {
+		want := p.Checksum(data)
+		for i := 0; i < 20; i++ {
+			split := rng.Intn(len(data) + 1)
+			crc1 := p.Checksum(data[:split])
+			crc2 := p.Checksum(data[split:])
+			if got := p.Combine(crc1, crc2, int64(len(data)-split)); got != want {
+				t.Fatalf("%s, split %d: got %#08x, want %#08x", p.Name, split, got, want)
+			}
+		}
+	}
+}
+
+func TestParamsMatchHashCRC32(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	for _, tc := range []struct {
+		p    Params
+		poly uint32
+	}{
+		{ISOHDLC, crc32.IEEE},
+		{ISCSI, crc32.Castagnoli},
+	} {
+		if got, want := tc.p.Checksum(data), crc32.Checksum(data, crc32.MakeTable(tc.poly)); got != want {
+			t.Errorf("%s: got %#08x, want %#08x", tc.p.Name, got, want)
+		}
+		if got, want := tc.p.Combine(1234, 5678, 910), CRC32Combine(tc.poly, 1234, 5678, 910); got != want {
+			t.Errorf("%s: Combine got %#08x, want %#08x", tc.p.Name, got, want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if p, ok := Lookup("CRC-32/MPEG-2"); !ok || p != MPEG2 {
+		t.Errorf("Lookup(CRC-32/MPEG-2) = %v, %v", p, ok)
+	}
+	if _, ok := Lookup("CRC-32/NONEXISTENT"); ok {
+		t.Errorf("Lookup found a nonexistent algorithm")
+	}
+}
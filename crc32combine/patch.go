@@ -0,0 +1,34 @@
+package crc32combine
+
+import (
+	"errors"
+	"hash/crc32"
+)
+
+// UpdateRegion returns the CRC-32 hash value of data after the bytes at
+// offset have been changed from oldBytes to newBytes, given the hash value
+// oldCRC and byte length totalLen of the data before the change. poly
+// represents the generator polynomial.
+//
+// Since the CRC is linear, only the difference between the old and new bytes
+// needs hashing, and that hash is then shifted past the rest of the data, so
+// the cost does not depend on totalLen.
+func UpdateRegion(poly uint32, oldCRC uint32, totalLen, offset int64, oldBytes, newBytes []byte) (uint32, error) {
+	if len(oldBytes) != len(newBytes) {
+		return 0, errors.New("crc32combine: old and new bytes differ in length")
+	}
+	if offset < 0 || offset+int64(len(oldBytes)) > totalLen {
+		return 0, errors.New("crc32combine: region out of range")
+	}
+
+	diff := make([]byte, len(oldBytes))
+	for i := range diff {
+		diff[i] = oldBytes[i] ^ newBytes[i]
+	}
+
+	// Hash the difference with a zero register and no final XOR;
+	// crc32.Update complements the register on the way in and out.
+	reg := ^crc32.Update(^uint32(0), crc32.MakeTable(poly), diff)
+	tail := totalLen - offset - int64(len(diff))
+	return oldCRC ^ uint32(shiftZeros(reflectedBitOp(uint64(poly), 32), uint64(reg), tail)), nil
+}
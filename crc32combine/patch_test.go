@@ -0,0 +1,50 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestUpdateRegion(t *testing.T) {
+	rng := rand.New(rand.NewSource(38))
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		data := make([]byte, 10000)
+		rng.Read(data)
+		crc := crc32.Checksum(data, tab)
+
+		for i := 0; i < 50; i++ {
+			off := rng.Intn(len(data) + 1)
+			n := rng.Intn(len(data) - off + 1)
+			if n > 100 {
+				n = 100
+			}
+			oldBytes := append([]byte(nil), data[off:off+n]...)
+			newBytes := make([]byte, n)
+			rng.Read(newBytes)
+			copy(data[off:], newBytes)
+
+			var err error
+			crc, err = UpdateRegion(poly, crc, int64(len(data)), int64(off), oldBytes, newBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := crc32.Checksum(data, tab); crc != want {
+				t.Fatalf("poly %#x, patch %d bytes at %d: got %#08x, want %#08x", poly, n, off, crc, want)
+			}
+		}
+	}
+}
+
+func TestUpdateRegionErrors(t *testing.T) {
+	if _, err := UpdateRegion(crc32.IEEE, 0, 10, 0, []byte{1}, []byte{1, 2}); err == nil {
+		t.Errorf("mismatched lengths should fail")
+	}
+	if _, err := UpdateRegion(crc32.IEEE, 0, 10, 8, []byte{1, 2, 3}, []byte{4, 5, 6}); err == nil {
+		t.Errorf("region past the end should fail")
+	}
+	if _, err := UpdateRegion(crc32.IEEE, 0, 10, -1, []byte{1}, []byte{2}); err == nil {
+		t.Errorf("negative offset should fail")
+	}
+}
@@ -0,0 +1,78 @@
+package crc32combine
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+// A Rolling computes the CRC-32 of a fixed-size window of data that slides
+// one byte at a time, such as for content-defined chunking. Each Roll takes
+// constant time regardless of the window size.
+type Rolling struct {
+	tab    *crc32.Table
+	out    [256]uint32
+	window int
+	reg    uint32
+}
+
+// NewRolling returns a Rolling for windows of the given size in bytes,
+// using the generator polynomial poly. The window starts out as all zero
+// bytes; use Reset to set its initial content. The window must be at least
+// one byte long; NewRolling panics otherwise.
+func NewRolling(poly uint32, window int) *Rolling {
+	if window < 1 {
+		panic(fmt.Sprintf("crc32combine: invalid rolling window size %d", window))
+	}
+	r := &Rolling{
+		tab:    crc32.MakeTable(poly),
+		window: window,
+	}
+
+	// Feeding a byte b into the register adds tab[b], which has been
+	// shifted by window more bytes by the time b leaves the window. The
+	// initial register value gets shifted by one more byte on each Roll
+	// as well, so that is corrected too.
+	bitOp := reflectedBitOp(uint64(poly), 32)
+	n := int64(window)
+	fix := uint32(shiftZeros(bitOp, 0xffffffff, n+1) ^ shiftZeros(bitOp, 0xffffffff, n))
+	var bitsOut [8]uint32
+	for i := range bitsOut {
+		// The table is linear, so only the single bits need shifting.
+		bitsOut[i] = uint32(shiftZeros(bitOp, uint64(r.tab[1<<uint(i)]), n))
+	}
+	for b := range r.out {
+		v := fix
+		for i := range bitsOut {
+			if b&(1<<uint(i)) != 0 {
+				v ^= bitsOut[i]
+			}
+		}
+		r.out[b] = v
+	}
+
+	r.Reset(make([]byte, window))
+	return r
+}
+
+// Reset sets the content of the window, which must be exactly the size of
+// the window.
+func (r *Rolling) Reset(window []byte) error {
+	if len(window) != r.window {
+		return fmt.Errorf("crc32combine: got %d bytes for window of %d", len(window), r.window)
+	}
+	r.reg = ^crc32.Checksum(window, r.tab)
+	return nil
+}
+
+// Roll slides the window by one byte, removing out from the start of the
+// window and adding in to its end, and returns the CRC-32 of the new window.
+// out must be the first byte of the current window.
+func (r *Rolling) Roll(out, in byte) uint32 {
+	r.reg = r.tab[byte(r.reg)^in] ^ r.reg>>8 ^ r.out[out]
+	return ^r.reg
+}
+
+// Sum32 returns the CRC-32 of the current window.
+func (r *Rolling) Sum32() uint32 {
+	return ^r.reg
+}
@@ -0,0 +1,61 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestRolling(t *testing.T) {
+	rng := rand.New(rand.NewSource(39))
+	data := make([]byte, 2000)
+	rng.Read(data)
+
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		for _, window := range []int{1, 4, 48, 1000} {
+			r := NewRolling(poly, window)
+			if err := r.Reset(data[:window]); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := r.Sum32(), crc32.Checksum(data[:window], tab); got != want {
+				t.Fatalf("poly %#x, window %d: initial got %#08x, want %#08x", poly, window, got, want)
+			}
+			for i := window; i < len(data); i++ {
+				got := r.Roll(data[i-window], data[i])
+				if want := crc32.Checksum(data[i-window+1:i+1], tab); got != want {
+					t.Fatalf("poly %#x, window %d, position %d: got %#08x, want %#08x", poly, window, i, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRollingFromZeros(t *testing.T) {
+	r := NewRolling(crc32.IEEE, 8)
+	data := []byte("rolling from zeros")
+	window := make([]byte, 8)
+	for _, b := range data {
+		got := r.Roll(window[0], b)
+		window = append(window[1:], b)
+		if want := crc32.ChecksumIEEE(window); got != want {
+			t.Fatalf("window %q: got %#08x, want %#08x", window, got, want)
+		}
+	}
+	if err := r.Reset(data); err == nil {
+		t.Errorf("Reset with the wrong size should fail")
+	}
+}
+
+func TestRollingInvalidWindow(t *testing.T) {
+	for _, window := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("window %d: NewRolling did not panic", window)
+				}
+			}()
+			NewRolling(crc32.IEEE, window)
+		}()
+	}
+}
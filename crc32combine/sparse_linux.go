@@ -0,0 +1,76 @@
+//go:build linux
+// +build linux
+
+package crc32combine
+
+import (
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"syscall"
+)
+
+// whence values for lseek(2), not defined by package syscall.
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+// ChecksumSparseFile returns the CRC-32 checksum of the contents of f using
+// the given table. Only the data extents of f are read, and the holes
+// between them are accounted for with CRC32ExtendZeros, which makes it much
+// faster than reading the whole file when f is sparse. If the filesystem
+// does not report holes, f is read in full. ChecksumSparseFile changes the
+// file offset of f.
+func ChecksumSparseFile(f *os.File, tab *crc32.Table) (uint32, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := fi.Size()
+	comb := NewCombiner(tablePoly(tab))
+	buf := make([]byte, 64*1024)
+
+	var crc uint32
+	var off int64
+	for off < size {
+		data, err := f.Seek(off, seekData)
+		if errors.Is(err, syscall.ENXIO) {
+			// No data past off, so the rest of the file is a hole.
+			data = size
+		} else if errors.Is(err, syscall.EINVAL) && off == 0 {
+			// SEEK_DATA is not supported, so treat the file as all data.
+			data = 0
+		} else if err != nil {
+			return 0, err
+		}
+		crc = comb.ExtendZeros(crc, data-off)
+		if data >= size {
+			break
+		}
+
+		hole, err := f.Seek(data, seekHole)
+		if errors.Is(err, syscall.EINVAL) {
+			hole = size
+		} else if err != nil {
+			return 0, err
+		}
+		if hole > size {
+			hole = size
+		}
+
+		r := io.NewSectionReader(f, data, hole-data)
+		for {
+			n, err := r.Read(buf)
+			crc = crc32.Update(crc, tab, buf[:n])
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return 0, err
+			}
+		}
+		off = hole
+	}
+	return crc, nil
+}
@@ -0,0 +1,52 @@
+//go:build linux
+// +build linux
+
+package crc32combine
+
+import (
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestChecksumSparseFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "crc32combine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	tab := crc32.MakeTable(crc32.Castagnoli)
+	check := func(desc string) {
+		want, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ChecksumSparseFile(f, tab)
+		if err != nil {
+			t.Fatalf("%s: %v", desc, err)
+		}
+		if got != crc32.Checksum(want, tab) {
+			t.Errorf("%s: got %#x, want %#x", desc, got, crc32.Checksum(want, tab))
+		}
+	}
+
+	check("empty file")
+	if err := f.Truncate(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	check("all hole")
+	if _, err := f.WriteAt([]byte("data in the middle"), 300000); err != nil {
+		t.Fatal(err)
+	}
+	check("data in the middle")
+	if _, err := f.WriteAt([]byte("leading data"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("trailing data"), 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	check("data at both ends")
+}
@@ -0,0 +1,26 @@
+package crc32combine
+
+// CRC32Suffix returns the CRC-32 hash value of B, given the hash value crcAB
+// of the concatenation of A and B, the hash value crcA of A, and the byte
+// length lenB of B. poly represents the generator polynomial.
+func CRC32Suffix(poly uint32, crcAB, crcA uint32, lenB int64) uint32 {
+	// An empty B has a hash value of 0, whatever A is.
+	if lenB <= 0 {
+		return 0
+	}
+	// crcAB = shift(crcA, lenB) ^ crcB, so this is the same computation
+	// as combining crcA and crcAB.
+	return CRC32Combine(poly, crcA, crcAB, lenB)
+}
+
+// CRC32Prefix returns the CRC-32 hash value of A, given the hash value crcAB
+// of the concatenation of A and B, the hash value crcB of B, and the byte
+// length lenB of B. poly represents the generator polynomial, and must have
+// its x^0 term set, as is the case for all standard CRC-32 polynomials.
+func CRC32Prefix(poly uint32, crcAB, crcB uint32, lenB int64) uint32 {
+	if lenB <= 0 {
+		return crcAB
+	}
+	// Undo the shift of crcA by lenB zero bytes.
+	return uint32(shiftZeros(reflectedInverseBitOp(uint64(poly), 32), uint64(crcAB^crcB), lenB))
+}
@@ -0,0 +1,55 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestInverseBitOp(t *testing.T) {
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		op := reflectedBitOp(uint64(poly), 32)
+		inv := reflectedInverseBitOp(uint64(poly), 32)
+		for n := 0; n < 32; n++ {
+			if got := inv.times(op.times(1 << uint(n))); got != 1<<uint(n) {
+				t.Errorf("poly %#x: inverse of bit %d gave %#x", poly, n, got)
+			}
+		}
+	}
+}
+
+func TestCRC32SuffixPrefix(t *testing.T) {
+	rng := rand.New(rand.NewSource(34))
+	data := make([]byte, 1<<16)
+	rng.Read(data)
+
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		crcAB := crc32.Checksum(data, tab)
+
+		// Cover an empty A and an empty B, which random splits miss.
+		splits := []int{0, len(data)}
+		for i := 0; i < 50; i++ {
+			splits = append(splits, rng.Intn(len(data)+1))
+		}
+		for _, split := range splits {
+			crcA := crc32.Checksum(data[:split], tab)
+			crcB := crc32.Checksum(data[split:], tab)
+			lenB := int64(len(data) - split)
+
+			if got := CRC32Suffix(poly, crcAB, crcA, lenB); got != crcB {
+				t.Fatalf("poly %#x, split %d: CRC32Suffix = %#x, want %#x", poly, split, got, crcB)
+			}
+			if got := CRC32Prefix(poly, crcAB, crcB, lenB); got != crcA {
+				t.Fatalf("poly %#x, split %d: CRC32Prefix = %#x, want %#x", poly, split, got, crcA)
+			}
+		}
+	}
+}
+
+func TestCRC32SuffixEmpty(t *testing.T) {
+	crc := crc32.ChecksumIEEE([]byte("hello world"))
+	if got := CRC32Suffix(crc32.IEEE, crc, crc, 0); got != 0 {
+		t.Errorf("got %#08x, want 0", got)
+	}
+}
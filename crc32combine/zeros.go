@@ -0,0 +1,26 @@
+package crc32combine
+
+// CRC32Zeros returns the CRC-32 hash value of n zero bytes, in O(log n)
+// time. poly represents the generator polynomial.
+func CRC32Zeros(poly uint32, n int64) uint32 {
+	return CRC32ExtendZeros(poly, 0, n)
+}
+
+// CRC32ExtendZeros returns the CRC-32 hash value of the data covered by crc
+// followed by n zero bytes, in O(log n) time. poly represents the generator
+// polynomial.
+func CRC32ExtendZeros(poly uint32, crc uint32, n int64) uint32 {
+	if n <= 0 {
+		return crc
+	}
+	// The register holds the complement of the hash value.
+	return ^uint32(shiftZeros(reflectedBitOp(uint64(poly), 32), uint64(^crc), n))
+}
+
+// ExtendZeros is the equivalent of CRC32ExtendZeros for the polynomial of c.
+func (c *Combiner) ExtendZeros(crc uint32, n int64) uint32 {
+	if n <= 0 {
+		return crc
+	}
+	return ^uint32(c.ops.apply(uint64(^crc), n))
+}
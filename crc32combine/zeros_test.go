@@ -0,0 +1,29 @@
+package crc32combine
+
+import (
+	"hash/crc32"
+	"testing"
+)
+
+func TestCRC32Zeros(t *testing.T) {
+	for _, poly := range []uint32{crc32.IEEE, crc32.Castagnoli, crc32.Koopman} {
+		tab := crc32.MakeTable(poly)
+		comb := NewCombiner(poly)
+		prefix := []byte("some leading data")
+		for _, n := range []int{0, 1, 7, 100, 4096, 100000} {
+			zeros := make([]byte, n)
+			if got, want := CRC32Zeros(poly, int64(n)), crc32.Checksum(zeros, tab); got != want {
+				t.Errorf("poly %#x: CRC32Zeros(%d) = %#x, want %#x", poly, n, got, want)
+			}
+
+			crc := crc32.Checksum(prefix, tab)
+			want := crc32.Checksum(append(prefix, zeros...), tab)
+			if got := CRC32ExtendZeros(poly, crc, int64(n)); got != want {
+				t.Errorf("poly %#x: CRC32ExtendZeros(%d) = %#x, want %#x", poly, n, got, want)
+			}
+			if got := comb.ExtendZeros(crc, int64(n)); got != want {
+				t.Errorf("poly %#x: Combiner.ExtendZeros(%d) = %#x, want %#x", poly, n, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package exit
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// capturedExit is the panic that stops the goroutine running the function
+// passed to Capture when it exits.
+type capturedExit int
+
+// capture collects the exit code recorded while Capture is running.
+type capture struct {
+	once  sync.Once
+	codes chan int
+	// async is closed when the exit comes from another goroutine than the
+	// one running f, such as the cleanup deadline or a repeated signal.
+	async chan struct{}
+}
+
+func (c *capture) record(code int, async bool) {
+	c.once.Do(func() {
+		c.codes <- code
+		if async {
+			close(c.async)
+		}
+	})
+}
+
+var (
+	captureMu     sync.Mutex
+	activeCapture *capture // guarded by exitMu
+	runCapturedFn = runtime.FuncForPC(reflect.ValueOf(runCaptured).Pointer()).Name()
+)
+
+// Capture runs f, intercepting any exit it initiates, and returns the exit
+// code along with whether f tried to exit at all. It is meant for testing
+// code that calls Return, Request and Wait, or Main, without terminating the
+// test binary. A Return in f is intercepted whether or not f deferred
+// Recover; if it did, the deferred functions and hooks run as they would
+// for a real exit.
+//
+// f is run in a separate goroutine, which is stopped when f exits, and
+// Capture normally returns once that goroutine is done. An exit initiated
+// from another goroutine, such as the cleanup deadline expiring or a
+// repeated signal, makes Capture return immediately, leaving f to unwind on
+// its own. Other panics in f are propagated to the caller of Capture. Calls
+// to Capture are serialized, and the state of pending exit requests is reset
+// once Capture returns. Capture cannot be nested: it panics if called from
+// within f, and calling it from a goroutine started by f deadlocks. To check the behavior of the actual call to os.Exit,
+// see package exittest, which runs code in a subprocess.
+func Capture(f func()) (code int, exited bool) {
+	if onCapturedGoroutine() {
+		panic("exit: Capture called from within a function run by Capture")
+	}
+	captureMu.Lock()
+	defer captureMu.Unlock()
+
+	c := &capture{codes: make(chan int, 1), async: make(chan struct{})}
+	exitMu.Lock()
+	activeCapture = c
+	exitMu.Unlock()
+	defer func() {
+		exitMu.Lock()
+		activeCapture = nil
+		exitMu.Unlock()
+		stopDeadline()
+		resetRequest()
+	}()
+
+	done := make(chan interface{}, 1)
+	go runCaptured(f, c, done)
+
+	select {
+	case r := <-done:
+		if r != nil {
+			panic(r)
+		}
+	case <-c.async:
+	}
+	select {
+	case code = <-c.codes:
+		return code, true
+	default:
+		return 0, false
+	}
+}
+
+// runCaptured runs f on behalf of Capture. Exits initiated on its goroutine
+// are recognized by callExit from its presence on the stack, so that they
+// remain intercepted even after Capture has returned.
+func runCaptured(f func(), c *capture, done chan<- interface{}) {
+	returned := false
+	defer func() {
+		if returned {
+			done <- nil
+			return
+		}
+		r := recover()
+		switch code := r.(type) {
+		case capturedExit:
+			c.record(int(code), false)
+			r = nil
+		case exitCode:
+			c.record(int(code), false)
+			r = nil
+		}
+		done <- r
+	}()
+	f()
+	returned = true
+}
+
+// onCapturedGoroutine reports whether the calling goroutine was started by
+// Capture.
+func onCapturedGoroutine() bool {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(1, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function == runCapturedFn {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestCapture(t *testing.T) {
+	defer SetOutput(getOutput())
+	SetOutput(ioutil.Discard)
+
+	for _, tc := range []struct {
+		desc   string
+		f      func()
+		code   int
+		exited bool
+	}{
+		{"no exit", func() {}, 0, false},
+		{"return without recover", func() { Return(2) }, 2, true},
+		{"return with recover", func() {
+			defer Recover()
+			Return(3)
+		}, 3, true},
+		{"main", func() {
+			Main(func(context.Context) error { return WithCode(errors.New("bad flags"), Usage) })
+		}, Usage, true},
+		{"main success", func() {
+			Main(func(context.Context) error { return nil })
+		}, 0, true},
+		{"request and wait", func() {
+			defer Recover()
+			go Request(4)
+			Wait()
+		}, 4, true},
+	} {
+		cleanedUp := false
+		code, exited := Capture(func() {
+			defer func() { cleanedUp = true }()
+			tc.f()
+		})
+		if code != tc.code || exited != tc.exited {
+			t.Errorf("%s: got %v, %v, want %v, %v", tc.desc, code, exited, tc.code, tc.exited)
+		}
+		if !cleanedUp {
+			t.Errorf("%s: deferred cleanup did not run", tc.desc)
+		}
+	}
+
+	// Requests do not leak out of Capture.
+	if _, ok := requested(); ok {
+		t.Errorf("request still pending after Capture")
+	}
+}
+
+func TestCapturePanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "other panic" {
+			t.Errorf("got panic %v, want %v", r, "other panic")
+		}
+	}()
+
+	Capture(func() {
+		defer Recover()
+		panic("other panic")
+	})
+	t.Errorf("Capture did not propagate the panic")
+}
+
+func TestCaptureDeadline(t *testing.T) {
+	defer SetOutput(getOutput())
+	SetOutput(ioutil.Discard)
+	defer SetCleanupDeadline(0)
+	SetCleanupDeadline(20 * time.Millisecond)
+
+	unblock := make(chan struct{})
+	finished := make(chan struct{})
+	start := time.Now()
+	code, exited := Capture(func() {
+		defer close(finished)
+		defer Recover()
+		defer func() { <-unblock }()
+		Return(5)
+	})
+	if code != 5 || !exited {
+		t.Errorf("got %v, %v, want 5, true", code, exited)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Capture returned after %v, long after the deadline", d)
+	}
+
+	// Once unblocked, f finishes exiting without terminating the test.
+	close(unblock)
+	<-finished
+}
+
+func TestCaptureNested(t *testing.T) {
+	var r interface{}
+	Capture(func() {
+		defer func() { r = recover() }()
+		Capture(func() {})
+	})
+	if r == nil {
+		t.Errorf("nested Capture did not panic")
+	}
+}
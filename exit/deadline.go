@@ -0,0 +1,67 @@
+package exit
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	deadlineMu      sync.Mutex
+	cleanupDeadline time.Duration
+	deadlineTimer   *time.Timer
+)
+
+// SetCleanupDeadline sets the time the deferred functions and hooks run by
+// an exit are given to complete, counted from the start of the exit: the
+// first call to Request, which includes signals handled by HandleSignals, or
+// otherwise the call to Return. This also covers the functions deferred by
+// the function passed to Main while it returns. If they are still running
+// when the deadline passes, the stacks of all goroutines are written to the
+// writer set by SetOutput, and the program exits with the code of the exit
+// anyway. A deadline of 0, the default, disables this.
+func SetCleanupDeadline(d time.Duration) {
+	deadlineMu.Lock()
+	defer deadlineMu.Unlock()
+	cleanupDeadline = d
+}
+
+// startDeadline arms the cleanup deadline for an exit with the given code,
+// unless it is disabled or already armed.
+func startDeadline(code int) {
+	deadlineMu.Lock()
+	defer deadlineMu.Unlock()
+	if cleanupDeadline <= 0 || deadlineTimer != nil {
+		return
+	}
+	d := cleanupDeadline
+	deadlineTimer = time.AfterFunc(d, func() {
+		w := getOutput()
+		fmt.Fprintf(w, "exit: cleanup did not finish within %v\n\n", d)
+		fmt.Fprintln(w, string(allStacks()))
+		callExit(code)
+	})
+}
+
+// stopDeadline disarms the cleanup deadline.
+func stopDeadline() {
+	deadlineMu.Lock()
+	defer deadlineMu.Unlock()
+	if deadlineTimer != nil {
+		deadlineTimer.Stop()
+		deadlineTimer = nil
+	}
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 65536)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,106 @@
+package exit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCleanupDeadline(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer SetCleanupDeadline(0)
+
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetCleanupDeadline(20 * time.Millisecond)
+	codes := make(chan int, 2)
+	setExitFunc(func(c int) {
+		codes <- c
+	})
+
+	unblock := make(chan struct{})
+	go func() {
+		defer Recover()
+		defer func() { <-unblock }()
+		Return(9)
+	}()
+
+	select {
+	case code := <-codes:
+		if code != 9 {
+			t.Errorf("got code %v, want %v", code, 9)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no exit after cleanup deadline")
+	}
+	out.mu.Lock()
+	s := out.String()
+	out.mu.Unlock()
+	if !strings.Contains(s, "cleanup did not finish") || !strings.Contains(s, "goroutine ") {
+		t.Errorf("output does not contain stacks: %q", s)
+	}
+
+	close(unblock)
+	<-codes
+}
+
+func TestCleanupDeadlineMet(t *testing.T) {
+	defer SetCleanupDeadline(0)
+
+	SetCleanupDeadline(20 * time.Millisecond)
+	codes := make(chan int, 2)
+	setExitFunc(func(c int) {
+		codes <- c
+	})
+
+	func() {
+		defer Recover()
+		Return(10)
+	}()
+	if code := <-codes; code != 10 {
+		t.Errorf("got code %v, want %v", code, 10)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case code := <-codes:
+		t.Errorf("deadline fired after cleanup finished, with code %v", code)
+	default:
+	}
+}
+
+// A cleanup deferred by the function passed to Main must be bounded once an
+// exit is requested, before Main gets to call Return.
+func TestCleanupDeadlineMain(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer SetCleanupDeadline(0)
+	defer resetRequest()
+
+	SetOutput(&syncBuffer{})
+	SetCleanupDeadline(20 * time.Millisecond)
+	codes := make(chan int, 2)
+	setExitFunc(func(c int) {
+		codes <- c
+	})
+
+	unblock := make(chan struct{})
+	go Main(func(ctx context.Context) error {
+		defer func() { <-unblock }()
+		go Request(6)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	select {
+	case code := <-codes:
+		if code != 6 {
+			t.Errorf("got code %v, want %v", code, 6)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no exit after cleanup deadline")
+	}
+
+	close(unblock)
+	<-codes
+}
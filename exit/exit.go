@@ -55,45 +55,99 @@ NOTE: This mechanism only works if exit.Return() is called from the same
 goroutine that deferred exit.Recover(). Usually this means Return() should
 only be used from within main(), or within functions that are only ever
 called from main(). See Recover() and Return() for more details.
+
+Other goroutines can use exit.Request(int) instead, which makes a call to
+exit.Wait() in main() return through the same path. See Request() and Wait().
+exit.HandleSignals() uses the same path to exit gracefully on SIGINT, SIGTERM
+and SIGHUP.
+
+Alternatively, exit.Main(func(context.Context) error) takes care of deferring
+exit.Recover(), and maps the returned error to an exit code. See Main().
 */
 package exit
 
 import (
 	"os"
+	"sync"
 )
 
 type exitCode int
 
 var (
-	exitFunc = os.Exit // can be faked out for testing
+	exitMu   sync.Mutex
+	exitFunc = os.Exit // can be faked out for testing; guarded by exitMu
 )
 
+// setExitFunc replaces the function used to exit the process.
+func setExitFunc(f func(int)) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitFunc = f
+}
+
+// callExit exits the process with the given code, unless Capture intercepts
+// the exit. Exits from the goroutine running the function passed to Capture
+// stop that goroutine; exits from any other goroutine make Capture return
+// immediately, and callExit then returns to its caller.
+func callExit(code int) {
+	if onCapturedGoroutine() {
+		panic(capturedExit(code))
+	}
+	exitMu.Lock()
+	c, f := activeCapture, exitFunc
+	exitMu.Unlock()
+	if c != nil {
+		c.record(code, true)
+		return
+	}
+	f(code)
+}
+
 // Recover should be deferred as the first line of main(). It recovers the
 // panic initiated by Return and converts it to a call to os.Exit. Any
 // functions deferred after Recover in the main goroutine will be executed
-// prior to exiting. Recover will re-panic anything other than the panic it
-// expects from Return.
+// prior to exiting, followed by the hooks registered with OnExit. The hooks
+// also run if main returns normally, without calling Return. Recover will
+// re-panic anything other than the panic it expects from Return, unless
+// ReportPanics has been called.
 func Recover() {
 	doRecover(recover())
 }
 
 func doRecover(err interface{}) {
 	if err == nil {
+		// main is returning normally, which also exits the program.
+		runHooks()
+		stopDeadline()
 		return
 	}
 
 	switch code := err.(type) {
 	case exitCode:
-		exitFunc(int(code))
+		finish(int(code))
 	default:
-		panic(err)
+		pcode, ok := panicReporting()
+		if !ok {
+			panic(err)
+		}
+		writeCrashReport(err)
+		finish(pcode)
 	}
 }
 
+// finish runs the hooks and exits.
+func finish(code int) {
+	runHooks()
+	stopDeadline()
+	callExit(code)
+}
+
 // Return initiates a panic that sends the return code to the deferred Recover,
 // executing other deferred functions along the way. When the panic reaches
 // Recover, the return code will be passed to os.Exit. This should only be
-// called from the main goroutine.
+// called from the main goroutine. See SetCleanupDeadline to bound the time
+// taken by deferred functions.
 func Return(code int) {
+	startDeadline(code)
 	panic(exitCode(code))
 }
@@ -29,9 +29,9 @@ func TestReturn(t *testing.T) {
 func TestRecover(t *testing.T) {
 	var code int
 
-	exitFunc = func(c int) {
+	setExitFunc(func(c int) {
 		code = c
-	}
+	})
 
 	func() {
 		defer Recover()
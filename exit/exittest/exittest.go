@@ -0,0 +1,54 @@
+// Package exittest provides a helper to test code that terminates the
+// process, for cases where exit.Capture is not enough, such as checking the
+// behavior of a real call to os.Exit.
+package exittest
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// envVar is set in the environment of the subprocess to the name of the test
+// that should run f.
+const envVar = "GO_EXITTEST_NAME"
+
+// Run runs f in a subprocess of the test binary, and returns the exit code
+// of the subprocess along with its combined standard output and error. The
+// subprocess only runs the calling test, and exits with code 0 if f returns.
+// Code in the test before the call to Run is also executed in the
+// subprocess, so it should be free of side effects.
+//
+//	func TestBadFlags(t *testing.T) {
+//		code, _ := exittest.Run(t, func() {
+//			os.Args = []string{"prog", "-bogus"}
+//			main()
+//		})
+//		if code != 2 {
+//			t.Errorf("got exit code %d, want 2", code)
+//		}
+//	}
+func Run(t *testing.T, f func()) (code int, output []byte) {
+	if os.Getenv(envVar) == t.Name() {
+		f()
+		os.Exit(0)
+	}
+
+	// -test.run matches each level of subtests separately.
+	parts := strings.Split(t.Name(), "/")
+	for i, p := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(p) + "$"
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run="+strings.Join(parts, "/"))
+	cmd.Env = append(os.Environ(), envVar+"="+t.Name())
+	output, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), output
+	} else if err != nil {
+		t.Fatalf("exittest: running subprocess: %v", err)
+	}
+	return 0, output
+}
@@ -0,0 +1,37 @@
+package exittest_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/vimeo/go-util/exit"
+	"github.com/vimeo/go-util/exit/exittest"
+)
+
+func TestRun(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		f    func()
+		code int
+	}{
+		{"returns", func() {}, 0},
+		{"os.Exit", func() { os.Exit(5) }, 5},
+		{"exit.Return", func() {
+			defer exit.Recover()
+			defer fmt.Println("deferred cleanup")
+			exit.Return(exit.Usage)
+		}, exit.Usage},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			code, out := exittest.Run(t, tc.f)
+			if code != tc.code {
+				t.Errorf("got exit code %d, want %d\n%s", code, tc.code, out)
+			}
+			if tc.code == exit.Usage && !bytes.Contains(out, []byte("deferred cleanup")) {
+				t.Errorf("deferred cleanup did not run:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package exit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHookTimeout is the time each hook registered with OnExit is given
+// to complete, unless changed with SetHookTimeout.
+const DefaultHookTimeout = 10 * time.Second
+
+type hook struct {
+	name     string
+	priority int
+	seq      int
+	f        func(ctx context.Context) error
+}
+
+var (
+	hooksMu     sync.Mutex
+	hooks       []hook
+	hookSeq     int
+	hookTimeout = DefaultHookTimeout
+)
+
+// OnExit registers f to be run when main, having deferred Recover, returns
+// normally or exits through Return, including exits requested with Request
+// and passed on by Wait or Main. Hooks run after the functions deferred in
+// main, in decreasing order of priority; hooks of equal priority run in the
+// reverse order of their registration, like deferred functions. Each hook is
+// passed a context that expires after the timeout set by SetHookTimeout.
+// Errors, panics and timeouts of hooks are written to the writer set by
+// SetOutput, and do not prevent the remaining hooks from running. It is safe
+// to call OnExit from any goroutine.
+func OnExit(name string, priority int, f func(ctx context.Context) error) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hookSeq++
+	hooks = append(hooks, hook{name: name, priority: priority, seq: hookSeq, f: f})
+}
+
+// SetHookTimeout sets the time each hook registered with OnExit is given to
+// complete. A hook still running past its timeout is abandoned.
+func SetHookTimeout(d time.Duration) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hookTimeout = d
+}
+
+// runHooks runs and unregisters all registered hooks.
+func runHooks() {
+	hooksMu.Lock()
+	hs := hooks
+	hooks = nil
+	timeout := hookTimeout
+	hooksMu.Unlock()
+
+	sort.Slice(hs, func(i, j int) bool {
+		if hs[i].priority != hs[j].priority {
+			return hs[i].priority > hs[j].priority
+		}
+		return hs[i].seq > hs[j].seq
+	})
+
+	for _, h := range hs {
+		if err := runHook(h, timeout); err != nil {
+			fmt.Fprintf(getOutput(), "exit: hook %q: %v\n", h.name, err)
+		}
+	}
+}
+
+func runHook(h hook, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+}
@@ -0,0 +1,92 @@
+package exit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnExit(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer SetHookTimeout(DefaultHookTimeout)
+
+	var out bytes.Buffer
+	SetOutput(&out)
+	SetHookTimeout(20 * time.Millisecond)
+
+	var code int
+	setExitFunc(func(c int) {
+		code = c
+	})
+
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	OnExit("low", -1, record("low", nil))
+	OnExit("first", 0, record("first", nil))
+	OnExit("failing", 0, record("failing", errors.New("flush failed")))
+	OnExit("high", 10, record("high", nil))
+	OnExit("panicking", 5, func(context.Context) error { panic("boom") })
+	OnExit("hanging", 1, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	func() {
+		defer Recover()
+		defer func() { order = append(order, "deferred") }()
+		Return(7)
+	}()
+
+	if code != 7 {
+		t.Errorf("got code %v, want %v", code, 7)
+	}
+	want := []string{"deferred", "high", "failing", "first", "low"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("got order %v, want %v", order, want)
+	}
+	for _, s := range []string{`"failing": flush failed`, `"panicking": panic: boom`, `"hanging": timed out`} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output %q does not contain %q", out.String(), s)
+		}
+	}
+
+	// Hooks only run once.
+	order = nil
+	func() {
+		defer Recover()
+		Return(0)
+	}()
+	if len(order) != 0 {
+		t.Errorf("hooks ran again: %v", order)
+	}
+}
+
+func TestOnExitNormalReturn(t *testing.T) {
+	setExitFunc(func(c int) {
+		t.Errorf("exit called with code %v on a normal return", c)
+	})
+
+	hookRan := false
+	OnExit("flush", 0, func(context.Context) error {
+		hookRan = true
+		return nil
+	})
+
+	func() {
+		defer Recover()
+	}()
+
+	if !hookRan {
+		t.Errorf("hook did not run on a normal return")
+	}
+}
@@ -0,0 +1,99 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	outputMu sync.Mutex
+	output   io.Writer = os.Stderr
+)
+
+// SetOutput sets the writer that errors and other diagnostics of this
+// package are written to. The default is os.Stderr.
+func SetOutput(w io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	output = w
+}
+
+func getOutput() io.Writer {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	return output
+}
+
+// Error is an error carrying the exit code Main should exit with.
+type Error struct {
+	Err  error
+	Code int
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode wraps err so that Main exits with the given code when it is
+// returned. It returns nil if err is nil.
+func WithCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Err: err, Code: code}
+}
+
+// Main runs f, then exits with a code depending on the error it returned,
+// after running the functions deferred by f. It defers Recover itself, so
+// Return may still be called from within f.
+//
+// f is passed the context returned by Context, and should return once it is
+// done. If f returns an error wrapping an *Error, Main exits with its code.
+// Otherwise, if an exit was requested with Request, Main exits with the
+// requested code, and any other error makes Main exit with 1. The error, if
+// any, is written to the writer set by SetOutput, unless it is only the
+// cancellation of the context following a Request.
+//
+//	func main() {
+//		exit.Main(func(ctx context.Context) error {
+//			defer cleanup()
+//			...
+//			if err != nil {
+//				return exit.WithCode(err, 2)
+//			}
+//			...
+//			return nil
+//		})
+//	}
+func Main(f func(ctx context.Context) error) {
+	defer Recover()
+
+	err := f(Context())
+
+	reqCode, requested := requested()
+	var code int
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		code = e.Code
+	case requested:
+		code = reqCode
+	case err != nil:
+		code = 1
+	}
+
+	if err != nil && !(requested && errors.Is(err, context.Canceled)) {
+		fmt.Fprintf(getOutput(), "%s: %v\n", filepath.Base(os.Args[0]), err)
+	}
+	Return(code)
+}
@@ -0,0 +1,65 @@
+package exit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMainCodes(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer resetRequest()
+
+	errTest := errors.New("test error")
+	for _, tc := range []struct {
+		desc    string
+		f       func(ctx context.Context) error
+		code    int
+		message string
+	}{
+		{"nil", func(context.Context) error { return nil }, 0, ""},
+		{"plain error", func(context.Context) error { return errTest }, 1, "test error"},
+		{"with code", func(context.Context) error { return WithCode(errTest, 3) }, 3, "test error"},
+		{"wrapped with code", func(context.Context) error {
+			return fmt.Errorf("wrapped: %w", WithCode(errTest, 4))
+		}, 4, "wrapped: test error"},
+		{"return", func(context.Context) error { Return(5); return nil }, 5, ""},
+		{"request", func(ctx context.Context) error {
+			go Request(6)
+			<-ctx.Done()
+			return ctx.Err()
+		}, 6, ""},
+		{"request then error", func(ctx context.Context) error {
+			Request(6)
+			return errTest
+		}, 6, "test error"},
+	} {
+		resetRequest()
+		var out bytes.Buffer
+		SetOutput(&out)
+		code := -1
+		setExitFunc(func(c int) {
+			code = c
+		})
+
+		Main(tc.f)
+
+		if code != tc.code {
+			t.Errorf("%s: got code %v, want %v", tc.desc, code, tc.code)
+		}
+		if tc.message == "" && out.Len() != 0 {
+			t.Errorf("%s: unexpected output %q", tc.desc, out.String())
+		} else if !strings.Contains(out.String(), tc.message) {
+			t.Errorf("%s: output %q does not contain %q", tc.desc, out.String(), tc.message)
+		}
+	}
+}
+
+func TestWithCodeNil(t *testing.T) {
+	if err := WithCode(nil, 2); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
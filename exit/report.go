@@ -0,0 +1,43 @@
+package exit
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var (
+	panicMu     sync.Mutex
+	reportPanic bool
+	panicCode   int
+)
+
+// ReportPanics makes Recover handle panics other than the one initiated by
+// Return, instead of re-panicking them. Recover then writes a crash report
+// with the panic value and the stack of the goroutine where the panic
+// happened to the writer set by SetOutput, runs the hooks registered with
+// OnExit, and exits with the given code. Go itself exits with code 2 after an
+// unrecovered panic.
+func ReportPanics(code int) {
+	panicMu.Lock()
+	defer panicMu.Unlock()
+	reportPanic = true
+	panicCode = code
+}
+
+// panicReporting returns the code set by ReportPanics, and whether it was
+// called.
+func panicReporting() (int, bool) {
+	panicMu.Lock()
+	defer panicMu.Unlock()
+	return panicCode, reportPanic
+}
+
+// writeCrashReport writes the report for a panic with the given value. It
+// must be called from the deferred function that recovered the panic, while
+// the stack of the panicking goroutine is still intact.
+func writeCrashReport(r interface{}) {
+	fmt.Fprintf(getOutput(), "exit: recovered panic\ntime: %s\ntype: %T\nvalue: %v\nstack:\n%s\n",
+		time.Now().UTC().Format(time.RFC3339), r, r, debug.Stack())
+}
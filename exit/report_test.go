@@ -0,0 +1,52 @@
+package exit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReportPanics(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer func() {
+		reportPanic = false
+		panicCode = 0
+	}()
+
+	var out bytes.Buffer
+	SetOutput(&out)
+	ReportPanics(3)
+
+	var code int
+	setExitFunc(func(c int) {
+		code = c
+	})
+	hookRan := false
+	OnExit("cleanup", 0, func(context.Context) error {
+		hookRan = true
+		return nil
+	})
+
+	func() {
+		defer Recover()
+		defer func() {}()
+		panicInCleanup()
+	}()
+
+	if code != 3 {
+		t.Errorf("got code %v, want %v", code, 3)
+	}
+	if !hookRan {
+		t.Errorf("hook did not run")
+	}
+	for _, s := range []string{"value: kaboom", "type: string", "panicInCleanup"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("report does not contain %q:\n%s", s, out.String())
+		}
+	}
+}
+
+func panicInCleanup() {
+	panic("kaboom")
+}
@@ -0,0 +1,76 @@
+package exit
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	reqMu     sync.Mutex
+	reqCode   int
+	reqCtx    context.Context
+	reqCancel context.CancelFunc
+)
+
+func init() {
+	resetRequest()
+}
+
+// resetRequest clears any pending request. It is used by tests.
+func resetRequest() {
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	reqCode = 0
+	reqCtx, reqCancel = context.WithCancel(context.Background())
+}
+
+// Context returns the root context of the program, which is canceled once an
+// exit has been requested with Request. Long-running goroutines should stop
+// when it is done.
+func Context() context.Context {
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	return reqCtx
+}
+
+// Request asks the program to exit with the given code. Unlike Return, it
+// may be called from any goroutine: it cancels the context returned by
+// Context and wakes up Wait in the main goroutine, which then calls Return so
+// that the deferred functions of main still run. Only the code of the first
+// request is used.
+func Request(code int) {
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	if reqCtx.Err() != nil {
+		return
+	}
+	reqCode = code
+	reqCancel()
+	// The exit starts now, so functions deferred by goroutines unwinding
+	// after the cancellation are bounded too.
+	startDeadline(code)
+}
+
+// requested returns the code passed to Request, and whether there was a
+// request at all.
+func requested() (int, bool) {
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	return reqCode, reqCtx.Err() != nil
+}
+
+// Wait blocks until an exit is requested with Request, then calls Return
+// with the requested code. Like Return, it should only be called from the
+// main goroutine, after deferring Recover.
+//
+//	func main() {
+//		defer exit.Recover()
+//		defer cleanup()
+//		go worker(exit.Context()) // calls exit.Request(1) on failure
+//		exit.Wait()
+//	}
+func Wait() {
+	<-Context().Done()
+	code, _ := requested()
+	Return(code)
+}
@@ -0,0 +1,51 @@
+package exit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestWait(t *testing.T) {
+	defer resetRequest()
+
+	var code int
+	setExitFunc(func(c int) {
+		code = c
+	})
+
+	ctx := Context()
+	cleanedUp := false
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		Request(3)
+		Request(4)
+	}()
+
+	func() {
+		defer Recover()
+		defer func() { cleanedUp = true }()
+		Wait()
+	}()
+
+	if code != 3 {
+		t.Errorf("got %v, want %v", code, 3)
+	}
+	if !cleanedUp {
+		t.Errorf("deferred cleanup did not run")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("context not canceled after request")
+	}
+}
+
+func TestRequested(t *testing.T) {
+	defer resetRequest()
+
+	if _, ok := requested(); ok {
+		t.Errorf("exit requested before Request")
+	}
+	Request(0)
+	if code, ok := requested(); !ok || code != 0 {
+		t.Errorf("requested() = %v, %v", code, ok)
+	}
+}
@@ -0,0 +1,62 @@
+package exit
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// SignalCode returns the conventional exit code of a process terminated by
+// sig, which is 128 plus the signal number.
+func SignalCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// HandleSignals installs handlers for the given signals, or for SIGINT,
+// SIGTERM and SIGHUP if none are given. The first signal received starts a
+// graceful shutdown by calling Request with the code given by SignalCode, so
+// Wait or Main must be used in main for it to take effect. Any further
+// signal makes the program exit immediately with its code, without running
+// deferred functions or hooks. The returned function stops the handlers.
+func HandleSignals(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+	}
+
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sigs...)
+
+	go func() {
+		received := false
+		for {
+			select {
+			case sig := <-c:
+				code := SignalCode(sig)
+				if !received {
+					received = true
+					fmt.Fprintf(getOutput(), "exit: received %v, shutting down\n", sig)
+					Request(code)
+				} else {
+					fmt.Fprintf(getOutput(), "exit: received %v again, exiting immediately\n", sig)
+					callExit(code)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
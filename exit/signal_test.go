@@ -0,0 +1,57 @@
+//go:build !windows
+// +build !windows
+
+package exit
+
+import (
+	"bytes"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func TestHandleSignals(t *testing.T) {
+	defer SetOutput(getOutput())
+	defer resetRequest()
+
+	SetOutput(&syncBuffer{})
+	codes := make(chan int, 1)
+	setExitFunc(func(c int) {
+		codes <- c
+	})
+
+	stop := HandleSignals()
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case <-Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit not requested after first signal")
+	}
+	if code, _ := requested(); code != 128+int(syscall.SIGHUP) {
+		t.Errorf("got requested code %v, want %v", code, 128+int(syscall.SIGHUP))
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	select {
+	case code := <-codes:
+		if code != 128+int(syscall.SIGTERM) {
+			t.Errorf("got exit code %v, want %v", code, 128+int(syscall.SIGTERM))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no immediate exit after second signal")
+	}
+}
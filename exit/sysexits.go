@@ -0,0 +1,93 @@
+package exit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+)
+
+// Exit codes from the BSD sysexits.h, for use with Return, Request and
+// WithCode.
+const (
+	OK          = 0  // successful termination
+	Usage       = 64 // command line usage error
+	DataErr     = 65 // data format error
+	NoInput     = 66 // cannot open input
+	NoUser      = 67 // addressee unknown
+	NoHost      = 68 // host name unknown
+	Unavailable = 69 // service unavailable
+	Software    = 70 // internal software error
+	OSErr       = 71 // system error (e.g., can't fork)
+	OSFile      = 72 // critical OS file missing
+	CantCreate  = 73 // can't create (user) output file
+	IOErr       = 74 // input/output error
+	TempFail    = 75 // temp failure; user is invited to retry
+	Protocol    = 76 // remote error in protocol
+	NoPerm      = 77 // permission denied
+	Config      = 78 // configuration error
+)
+
+// Classify returns the exit code that best describes err:
+//
+//   - OK for a nil error
+//   - the code of an *Error wrapped by err
+//   - TempFail for context.DeadlineExceeded and network timeouts
+//   - Unavailable for other network errors
+//   - NoInput for os.ErrNotExist
+//   - NoPerm for os.ErrPermission
+//   - CantCreate for os.ErrExist
+//   - DataErr for io.ErrUnexpectedEOF and parsing errors from strconv and
+//     encoding/json
+//   - 1 for any other error.
+func Classify(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var numErr *strconv.NumError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return TempFail
+	case errors.As(err, &opErr):
+		if opErr.Timeout() {
+			return TempFail
+		}
+		return Unavailable
+	case errors.As(err, &dnsErr):
+		if dnsErr.Timeout() {
+			return TempFail
+		}
+		return Unavailable
+	case errors.Is(err, os.ErrNotExist):
+		return NoInput
+	case errors.Is(err, os.ErrPermission):
+		return NoPerm
+	case errors.Is(err, os.ErrExist):
+		return CantCreate
+	case errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &numErr),
+		errors.As(err, &syntaxErr),
+		errors.As(err, &typeErr):
+		return DataErr
+	}
+	return 1
+}
+
+// Temporary reports whether code indicates a failure that may succeed if
+// retried later, which is the case for TempFail and Unavailable.
+func Temporary(code int) bool {
+	return code == TempFail || code == Unavailable
+}
@@ -0,0 +1,52 @@
+package exit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	_, numErr := strconv.Atoi("twelve")
+	jsonErr := json.Unmarshal([]byte("{"), new(interface{}))
+	_, notExist := os.Open("/nonexistent/file")
+
+	for _, tc := range []struct {
+		err  error
+		code int
+	}{
+		{nil, OK},
+		{errors.New("plain"), 1},
+		{WithCode(errors.New("usage"), Usage), Usage},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), TempFail},
+		{&net.DNSError{Err: "timeout", IsTimeout: true}, TempFail},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, Unavailable},
+		{&net.OpError{Op: "dial", Err: os.ErrPermission}, Unavailable},
+		{notExist, NoInput},
+		{os.ErrPermission, NoPerm},
+		{os.ErrExist, CantCreate},
+		{io.ErrUnexpectedEOF, DataErr},
+		{numErr, DataErr},
+		{jsonErr, DataErr},
+	} {
+		if got := Classify(tc.err); got != tc.code {
+			t.Errorf("Classify(%v) = %d, want %d", tc.err, got, tc.code)
+		}
+	}
+}
+
+func TestTemporary(t *testing.T) {
+	for code, want := range map[int]bool{
+		OK: false, 1: false, Usage: false, TempFail: true, Unavailable: true, Software: false,
+	} {
+		if got := Temporary(code); got != want {
+			t.Errorf("Temporary(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
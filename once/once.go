@@ -9,20 +9,33 @@ import (
 )
 
 // Success is an object that will perform exactly one action if successful.
+// The zero value is ready to use.
+//
+// Compatibility note: Success used to embed a sync.Cond. It no longer does,
+// so the promoted L field and Wait, Signal and Broadcast methods are gone;
+// code relying on them must use its own synchronization.
 type Success struct {
 	// This is an atomic instead of, say, a bool so that callers can hot-path without acquiring a lock.
 	done uint32
-	// This Cond protects the running bool, so that only one Do() execution is happening at once.
-	sync.Cond
-	// Running is set to true when a goroutine is calling the provided function.
-	running bool
+	// This channel holds a token while a goroutine is calling the provided function, so that only one
+	// Do() execution is happening at once. Unlike a lock, waiting for it can be abandoned.
+	running chan struct{}
+	// This channel is closed once the function has succeeded, to wake up all waiters at once.
+	finished chan struct{}
+	// This creates the channels on first use, so that the zero value works.
+	initOnce sync.Once
 }
 
 // New returns a Success, ready to use.
 func New() *Success {
-	o := &Success{}
-	o.L = &sync.Mutex{}
-	return o
+	return &Success{}
+}
+
+func (o *Success) init() {
+	o.initOnce.Do(func() {
+		o.running = make(chan struct{}, 1)
+		o.finished = make(chan struct{})
+	})
 }
 
 // Do calls the function f if and only if Do is being called for the
@@ -41,26 +54,25 @@ func New() *Success {
 // If f panics, Do considers it to have returned with an error, so future calls
 // of Do will invoke f again.
 //
-// If the context has been canceled before f is called successfully,
-// context.Canceled will be returned. Callers are responsible to gracefully handle
-// this event.
+// If the context is canceled before f is called successfully, the context's
+// error will be returned. A call to Do waiting for another goroutine's call
+// to f returns as soon as its own context is done. Callers are responsible to
+// gracefully handle this event.
 func (o *Success) Do(ctx context.Context, f func() error) error {
 	if atomic.LoadUint32(&o.done) != 0 {
 		return nil
 	}
+	o.init()
 
-	o.L.Lock()
-	defer o.L.Unlock()
-
-	for o.running {
-		if err := ctx.Err(); err != nil {
-			return err
-		}
-		o.Wait()
+	select {
+	case o.running <- struct{}{}:
+	case <-o.finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	o.running = true
 	defer func() {
-		o.running = false
+		<-o.running
 	}()
 
 	if err := ctx.Err(); err != nil {
@@ -71,13 +83,11 @@ func (o *Success) Do(ctx context.Context, f func() error) error {
 	}
 
 	if err := o.invoke(f); err != nil {
-		// Wake up just one goroutine to make the next attempt.
-		o.Signal()
 		return err
 	}
 
 	atomic.StoreUint32(&o.done, 1)
-	o.Broadcast()
+	close(o.finished)
 	return nil
 }
 
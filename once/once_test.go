@@ -51,3 +51,101 @@ func TestPanic(t *testing.T) {
 		t.Fatalf("wanted error, got %v", err)
 	}
 }
+
+// Make sure that a waiting Do returns as soon as its own context is done,
+// while another goroutine is still running f.
+func TestPromptCancel(t *testing.T) {
+	o := once.New()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slowDone := make(chan error)
+	go func() {
+		slowDone <- o.Do(context.Background(), func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	waitDone := make(chan error)
+	go func() {
+		waitDone <- o.Do(ctx, func() error {
+			t.Error("f called while another call was running")
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-waitDone:
+		if err != context.DeadlineExceeded {
+			t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting Do did not return after its context expired")
+	}
+
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Errorf("slow Do returned %v", err)
+	}
+	if err := o.Do(context.Background(), func() error {
+		t.Error("f called after success")
+		return nil
+	}); err != nil {
+		t.Errorf("Do after success returned %v", err)
+	}
+}
+
+// Make sure that canceled waiters don't let two calls of f run at once.
+func TestOneRunner(t *testing.T) {
+	o := once.New()
+	var running, calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%5)*time.Millisecond)
+			defer cancel()
+			o.Do(ctx, func() error {
+				if atomic.AddInt32(&running, 1) != 1 {
+					t.Error("more than one call of f running")
+				}
+				defer atomic.AddInt32(&running, -1)
+				time.Sleep(time.Millisecond)
+				if atomic.AddInt32(&calls, 1) < 10 {
+					return errors.New("errored")
+				}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if calls > 10 {
+		t.Errorf("calls = %d after success", calls)
+	}
+}
+
+// Make sure that the zero value is usable, rather than blocking forever.
+func TestZeroValue(t *testing.T) {
+	var o once.Success
+	calls := 0
+	for i := 0; i < 3; i++ {
+		if err := o.Do(context.Background(), func() error {
+			calls++
+			if calls < 2 {
+				return errors.New("errored")
+			}
+			return nil
+		}); (err == nil) != (i > 0) {
+			t.Errorf("call %d: got error %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d", calls)
+	}
+}
@@ -0,0 +1,41 @@
+package once
+
+import (
+	"context"
+)
+
+// Value lazily computes a value with a function that is retried until it
+// succeeds, and then caches its result.
+type Value struct {
+	s *Success
+	f func(ctx context.Context) (interface{}, error)
+	v interface{}
+}
+
+// NewValue returns a Value computed by f.
+func NewValue(f func(ctx context.Context) (interface{}, error)) *Value {
+	return &Value{
+		s: New(),
+		f: f,
+	}
+}
+
+// Get returns the value computed by f. If f has not succeeded yet, Get calls
+// it with ctx, and returns the error if it fails, so that a later call to Get
+// tries again. Only one call to f runs at a time, and as with Success.Do, a
+// panic in f is returned as an error, and the error of ctx is returned if it
+// is canceled before f succeeds.
+func (v *Value) Get(ctx context.Context) (interface{}, error) {
+	err := v.s.Do(ctx, func() error {
+		val, err := v.f(ctx)
+		if err != nil {
+			return err
+		}
+		v.v = val
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.v, nil
+}
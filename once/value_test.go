@@ -0,0 +1,80 @@
+package once_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vimeo/go-util/once"
+)
+
+func TestValue(t *testing.T) {
+	var calls uint32
+	v := once.NewValue(func(ctx context.Context) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		if atomic.AddUint32(&calls, 1) < 3 {
+			return nil, errors.New("not yet")
+		}
+		return "value", nil
+	})
+
+	var wg sync.WaitGroup
+	var successes uint32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := v.Get(context.Background())
+			if err != nil {
+				return
+			}
+			atomic.AddUint32(&successes, 1)
+			if val != "value" {
+				t.Errorf("got %v, want %v", val, "value")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if successes != 18 {
+		t.Errorf("successes = %d, want 18", successes)
+	}
+	if val, err := v.Get(context.Background()); err != nil || val != "value" {
+		t.Errorf("cached Get = %v, %v", val, err)
+	}
+}
+
+func TestValuePanic(t *testing.T) {
+	first := true
+	v := once.NewValue(func(ctx context.Context) (interface{}, error) {
+		if first {
+			first = false
+			panic("panic'd")
+		}
+		return 42, nil
+	})
+
+	if val, err := v.Get(context.Background()); err == nil {
+		t.Fatalf("wanted error, got %v", val)
+	}
+	if val, err := v.Get(context.Background()); err != nil || val != 42 {
+		t.Errorf("got %v, %v after panic", val, err)
+	}
+}
+
+func TestValueCanceled(t *testing.T) {
+	v := once.NewValue(func(ctx context.Context) (interface{}, error) {
+		return 1, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.Get(ctx); err != context.Canceled {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}